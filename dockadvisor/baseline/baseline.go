@@ -0,0 +1,137 @@
+// Package baseline records known dockadvisor findings so that later runs
+// only report new ones.
+//
+// Findings are matched by their parse.Rule Fingerprint, which identifies a
+// finding by its rule code, build stage and instruction rather than by line
+// number, so a baseline keeps matching when unrelated lines are added or
+// removed.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+// FileName is the default name of the baseline file
+const FileName = ".dockadvisor-baseline.json"
+
+// Version is the version of the baseline file layout
+const Version = 1
+
+// Baseline is a set of known findings
+type Baseline struct {
+	Version  int       `json:"version"`
+	Findings []Finding `json:"findings"`
+
+	// dir is the directory file paths are relative to
+	dir string
+}
+
+// Finding is a known finding. The code is kept next to the fingerprint so
+// that the file can be reviewed.
+type Finding struct {
+	File        string `json:"file"`
+	Code        string `json:"code"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// New returns an empty baseline whose file paths are relative to dir, the
+// directory the baseline file is written to
+func New(dir string) *Baseline {
+	return &Baseline{Version: Version, Findings: []Finding{}, dir: dir}
+}
+
+// Load reads the baseline file at path
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := New(filepath.Dir(path))
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("%s: unsupported baseline version %d, expected %d", path, b.Version, Version)
+	}
+	return b, nil
+}
+
+// Add records the findings of the Dockerfile at path
+func (b *Baseline) Add(path string, rules []parse.Rule) {
+	file := b.relative(path)
+	for _, rule := range rules {
+		b.Findings = append(b.Findings, Finding{File: file, Code: rule.Code, Fingerprint: rule.Fingerprint})
+	}
+}
+
+// Write writes the baseline as indented JSON, sorted so that the file
+// produces small diffs when it is regenerated
+func (b *Baseline) Write(w io.Writer) error {
+	sort.Slice(b.Findings, func(i, j int) bool {
+		x, y := b.Findings[i], b.Findings[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Code != y.Code {
+			return x.Code < y.Code
+		}
+		return x.Fingerprint < y.Fingerprint
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(b)
+}
+
+// Filter removes the known findings from the result of the Dockerfile at
+// path, rescores it and returns the number of findings removed. Identical
+// findings have distinct fingerprints, so a recorded finding hides exactly
+// one of them.
+func (b *Baseline) Filter(path string, result *parse.Result) int {
+	file := b.relative(path)
+
+	known := map[string]bool{}
+	for _, finding := range b.Findings {
+		if finding.File == file {
+			known[finding.Fingerprint] = true
+		}
+	}
+	if len(known) == 0 {
+		return 0
+	}
+
+	kept := make([]parse.Rule, 0, len(result.Rules))
+	for _, rule := range result.Rules {
+		if !known[rule.Fingerprint] {
+			kept = append(kept, rule)
+		}
+	}
+
+	removed := len(result.Rules) - len(kept)
+	result.Rules = kept
+	result.Rescore()
+	return removed
+}
+
+// relative returns path relative to the baseline directory with forward
+// slashes, so the baseline works from any working directory and platform
+func (b *Baseline) relative(path string) string {
+	if b.dir != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			if dir, err := filepath.Abs(b.dir); err == nil {
+				if rel, err := filepath.Rel(dir, abs); err == nil {
+					path = rel
+				}
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
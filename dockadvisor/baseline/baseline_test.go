@@ -0,0 +1,170 @@
+package baseline
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+// record lints content, adds its findings to a new baseline in dir and
+// returns the baseline read back from disk
+func record(t *testing.T, dir, path, content string) *Baseline {
+	t.Helper()
+
+	result, err := parse.ParseDockerfile(content)
+	require.NoError(t, err)
+
+	b := New(dir)
+	b.Add(path, result.Rules)
+
+	baselinePath := filepath.Join(dir, FileName)
+	var buf bytes.Buffer
+	require.NoError(t, b.Write(&buf))
+	require.NoError(t, os.WriteFile(baselinePath, buf.Bytes(), 0o644))
+
+	loaded, err := Load(baselinePath)
+	require.NoError(t, err)
+	return loaded
+}
+
+func TestAdd(t *testing.T) {
+	dir := t.TempDir()
+	content := `FROM alpine AS Builder
+WORKDIR  app
+FROM alpine
+WORKDIR app
+WORKDIR app
+`
+	b := record(t, dir, filepath.Join(dir, "services", "api", "Dockerfile"), content)
+
+	result, err := parse.ParseDockerfile(content)
+	require.NoError(t, err)
+	expected := []Finding{}
+	for _, rule := range result.Rules {
+		expected = append(expected, Finding{File: "services/api/Dockerfile", Code: rule.Code, Fingerprint: rule.Fingerprint})
+	}
+	require.ElementsMatch(t, expected, b.Findings, "findings are recorded with the fingerprint of the rule")
+	require.Len(t, b.Findings, 4, "identical findings are recorded separately")
+}
+
+func TestFilter(t *testing.T) {
+	original := `FROM alpine AS build
+WORKDIR app
+RUN make
+`
+
+	tests := []struct {
+		name          string
+		content       string
+		expectedCodes []string
+		expectedScore int
+	}{
+		{
+			name:          "unchanged file",
+			content:       original,
+			expectedCodes: []string{},
+			expectedScore: 100,
+		},
+		{
+			name: "lines shifted",
+			content: `# syntax=docker/dockerfile:1
+
+FROM alpine AS build
+LABEL org.opencontainers.image.title=app
+WORKDIR   app
+RUN make
+`,
+			expectedCodes: []string{},
+			expectedScore: 100,
+		},
+		{
+			name: "new finding",
+			content: `FROM alpine AS build
+WORKDIR app
+MAINTAINER me
+`,
+			expectedCodes: []string{"MaintainerDeprecated"},
+			expectedScore: 95,
+		},
+		{
+			name: "same finding in another stage",
+			content: `FROM alpine AS build
+WORKDIR app
+FROM alpine AS runtime
+WORKDIR app
+`,
+			expectedCodes: []string{"WorkdirRelativePath"},
+			expectedScore: 95,
+		},
+		{
+			name: "more occurrences than recorded",
+			content: `FROM alpine AS build
+WORKDIR app
+WORKDIR app
+`,
+			expectedCodes: []string{"WorkdirRelativePath"},
+			expectedScore: 95,
+		},
+		{
+			name: "changed instruction",
+			content: `FROM alpine AS build
+WORKDIR src
+`,
+			expectedCodes: []string{"WorkdirRelativePath"},
+			expectedScore: 95,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "Dockerfile")
+			b := record(t, dir, path, original)
+
+			result, err := parse.ParseDockerfile(tt.content)
+			require.NoError(t, err)
+			before := len(result.Rules)
+
+			removed := b.Filter(path, result)
+
+			codes := []string{}
+			for _, rule := range result.Rules {
+				codes = append(codes, rule.Code)
+			}
+			require.Equal(t, tt.expectedCodes, codes)
+			require.Equal(t, before-len(codes), removed)
+			require.Equal(t, tt.expectedScore, result.Score)
+		})
+	}
+}
+
+func TestFilterOtherFile(t *testing.T) {
+	dir := t.TempDir()
+	content := "FROM alpine\nWORKDIR app\n"
+	b := record(t, dir, filepath.Join(dir, "Dockerfile"), content)
+
+	result, err := parse.ParseDockerfile(content)
+	require.NoError(t, err)
+	require.Zero(t, b.Filter(filepath.Join(dir, "api", "Dockerfile"), result))
+	require.Len(t, result.Rules, 1)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Load(filepath.Join(dir, FileName))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(dir, "newer.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 2, "findings": []}`), 0o644))
+	_, err = Load(path)
+	require.ErrorContains(t, err, "unsupported baseline version 2")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": `), 0o644))
+	_, err = Load(path)
+	require.Error(t, err)
+}
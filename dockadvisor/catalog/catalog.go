@@ -0,0 +1,193 @@
+// Package catalog describes the rules dockadvisor reports, combining the
+// metadata of the registered checks with the documentation embedded from
+// docs/checks.
+//
+// Each documentation file starts with a front matter naming the rule code it
+// documents, a one-line summary and a category: the instruction the rule is
+// about, or "global". The body follows the layout of the BuildKit build
+// check reference, with Output, Description and Examples sections; examples
+// are a "❌ Bad:" or "✅ Good:" line followed by a dockerfile code block.
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/deckrun/dockadvisor/docs"
+	"github.com/deckrun/dockadvisor/parse"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule describes a rule code
+type Rule struct {
+	Code     string         `json:"code"`
+	Severity parse.Severity `json:"severity"` // the default severity
+	Category string         `json:"category"`
+	Summary  string         `json:"summary"`
+	BuildKit bool           `json:"buildkit"` // also a BuildKit build check
+	OptIn    bool           `json:"optIn"`    // off unless a preset or the configuration turns it on
+	Options  []string       `json:"options,omitempty"`
+	Examples []Example      `json:"examples"`
+
+	// Doc is the Markdown documentation of the rule, without its front
+	// matter. It is empty for a rule of a check that isn't built in.
+	Doc string `json:"doc"`
+}
+
+// Example is a Dockerfile showing the rule
+type Example struct {
+	Good        bool   `json:"good"` // set when the rule reports nothing
+	Description string `json:"description"`
+	Dockerfile  string `json:"dockerfile"`
+}
+
+// document is a parsed documentation file
+type document struct {
+	Title    string `yaml:"title"`
+	Summary  string `yaml:"summary"`
+	Category string `yaml:"category"`
+
+	body     string
+	examples []Example
+}
+
+// documents maps rule codes to their documentation. The files are embedded,
+// so a file that can't be parsed is a bug of the build and panics.
+var documents = sync.OnceValue(func() map[string]document {
+	byCode := map[string]document{}
+	err := fs.WalkDir(docs.Checks, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(docs.Checks, path)
+		if err != nil {
+			return err
+		}
+		doc, err := parseDocument(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if _, ok := byCode[doc.Title]; ok {
+			return fmt.Errorf("%s: %s is documented twice", path, doc.Title)
+		}
+		byCode[doc.Title] = doc
+		return nil
+	})
+	if err != nil {
+		panic("catalog: " + err.Error())
+	}
+	return byCode
+})
+
+// Rules returns every rule code the registered checks report, sorted by code
+func Rules() []Rule {
+	metadata := parse.Rules()
+	rules := make([]Rule, 0, len(metadata))
+	for _, rule := range metadata {
+		rules = append(rules, newRule(rule))
+	}
+	return rules
+}
+
+// Lookup returns the rule with the given code, ignoring case
+func Lookup(code string) (Rule, bool) {
+	for _, rule := range parse.Rules() {
+		if strings.EqualFold(rule.Code, code) {
+			return newRule(rule), true
+		}
+	}
+	return Rule{}, false
+}
+
+func newRule(metadata parse.RuleMetadata) Rule {
+	doc := documents()[metadata.Code]
+	examples := doc.examples
+	if examples == nil {
+		examples = []Example{}
+	}
+	return Rule{
+		Code:     metadata.Code,
+		Severity: metadata.Severity,
+		Category: doc.Category,
+		Summary:  doc.Summary,
+		BuildKit: metadata.BuildKit,
+		OptIn:    metadata.OptIn,
+		Options:  metadata.Options,
+		Examples: examples,
+		Doc:      doc.body,
+	}
+}
+
+// parseDocument parses a documentation file: its front matter, body and
+// examples
+func parseDocument(data []byte) (document, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	rest, ok := bytes.CutPrefix(data, []byte("---\n"))
+	if !ok {
+		return document{}, fmt.Errorf("missing front matter")
+	}
+	frontMatter, body, ok := bytes.Cut(rest, []byte("\n---\n"))
+	if !ok {
+		return document{}, fmt.Errorf("unterminated front matter")
+	}
+
+	var doc document
+	if err := yaml.Unmarshal(frontMatter, &doc); err != nil {
+		return document{}, fmt.Errorf("invalid front matter: %w", err)
+	}
+	switch {
+	case doc.Title == "":
+		return document{}, fmt.Errorf("missing title")
+	case doc.Summary == "":
+		return document{}, fmt.Errorf("missing summary")
+	case doc.Category == "":
+		return document{}, fmt.Errorf("missing category")
+	}
+
+	doc.body = strings.TrimSpace(string(body)) + "\n"
+	doc.examples = parseExamples(doc.body)
+	return doc, nil
+}
+
+// parseExamples returns the examples of a documentation body: the dockerfile
+// code blocks following a "❌ Bad:" or "✅ Good:" paragraph
+func parseExamples(body string) []Example {
+	var examples []Example
+	var pending *Example
+	var block []string
+	inBlock := false
+
+	for _, line := range strings.Split(body, "\n") {
+		switch {
+		case inBlock && strings.HasPrefix(line, "```"):
+			inBlock = false
+			if pending != nil {
+				pending.Dockerfile = strings.Join(block, "\n") + "\n"
+				examples = append(examples, *pending)
+				pending = nil
+			}
+		case inBlock:
+			block = append(block, line)
+		case strings.HasPrefix(line, "```"):
+			inBlock = true
+			block = nil
+			if line != "```dockerfile" {
+				// Only Dockerfiles are examples, e.g. not the output
+				pending = nil
+			}
+		default:
+			if description, ok := strings.CutPrefix(line, "❌ Bad:"); ok {
+				pending = &Example{Good: false, Description: strings.TrimSpace(description)}
+			} else if description, ok := strings.CutPrefix(line, "✅ Good:"); ok {
+				pending = &Example{Good: true, Description: strings.TrimSpace(description)}
+			} else if pending != nil && line != "" {
+				// The description goes on
+				pending.Description += " " + strings.TrimSpace(line)
+			}
+		}
+	}
+	return examples
+}
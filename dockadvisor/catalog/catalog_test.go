@@ -0,0 +1,167 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+	rules := Rules()
+	require.Len(t, rules, len(parse.Rules()))
+
+	for _, rule := range rules {
+		require.NotEmpty(t, rule.Doc, "%s has no documentation in docs/checks", rule.Code)
+		require.NotEmpty(t, rule.Summary, rule.Code)
+		require.NotEmpty(t, rule.Category, rule.Code)
+		require.Contains(t, rule.Doc, "## Output", rule.Code)
+		require.Contains(t, rule.Doc, "## Description", rule.Code)
+
+		if rule.Code == "ParserWarning" {
+			// Parser warnings are whatever the parser reports
+			continue
+		}
+		bad := false
+		for _, example := range rule.Examples {
+			require.NotEmpty(t, example.Description, rule.Code)
+			require.NotEmpty(t, example.Dockerfile, rule.Code)
+			bad = bad || !example.Good
+		}
+		require.True(t, bad, "%s has no bad example", rule.Code)
+	}
+}
+
+func TestDocumentsAreRules(t *testing.T) {
+	for code := range documents() {
+		if code == "InvalidDefinitionDescription" {
+			// An experimental BuildKit check that dockadvisor doesn't report
+			continue
+		}
+		_, ok := parse.LookupRule(code)
+		require.True(t, ok, "%s is documented but not reported by any check", code)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	rule, ok := Lookup("workdirrelativepath")
+	require.True(t, ok)
+	require.Equal(t, "WorkdirRelativePath", rule.Code)
+	require.Equal(t, parse.SeverityWarning, rule.Severity)
+	require.Equal(t, "WORKDIR", rule.Category)
+	require.True(t, rule.BuildKit)
+	require.Equal(t, []Example{
+		{
+			Good:        false,
+			Description: "this assumes that `WORKDIR` in the base image is `/` (if that changes upstream, the `web` stage is broken).",
+			Dockerfile:  "FROM nginx AS web\nWORKDIR usr/share/nginx/html\nCOPY public .\n",
+		},
+		{
+			Good:        true,
+			Description: "a leading slash ensures that `WORKDIR` always ends up at the desired path.",
+			Dockerfile:  "FROM nginx AS web\nWORKDIR /usr/share/nginx/html\nCOPY public .\n",
+		},
+	}, rule.Examples)
+
+	rule, ok = Lookup("RunInvalidNetworkFlag")
+	require.True(t, ok)
+	require.Equal(t, []string{"networks"}, rule.Options)
+	require.False(t, rule.OptIn)
+
+	rule, ok = Lookup("UserRoot")
+	require.True(t, ok)
+	require.True(t, rule.OptIn)
+
+	_, ok = Lookup("InvalidDefinitionDescription")
+	require.False(t, ok, "only reported rules are in the catalog")
+	_, ok = Lookup("LatestTag")
+	require.False(t, ok)
+}
+
+func TestParseDocument(t *testing.T) {
+	doc, err := parseDocument([]byte("---\ntitle: LatestTag\nsummary: \"Pin images: never use latest\"\ncategory: FROM\n---\n\n## Output\n\n```text\nPin the base image\n```\n\n## Examples\n\n❌ Bad: the image\nisn't pinned.\n\n```dockerfile\nFROM alpine:latest\n```\n\n✅ Good: a version is used.\n\n```dockerfile\nFROM alpine:3.20\n```\n"))
+	require.NoError(t, err)
+	require.Equal(t, "LatestTag", doc.Title)
+	require.Equal(t, "Pin images: never use latest", doc.Summary)
+	require.Equal(t, "FROM", doc.Category)
+	require.Equal(t, "## Output\n\n```text\nPin the base image\n```\n\n## Examples\n\n❌ Bad: the image\nisn't pinned.\n\n```dockerfile\nFROM alpine:latest\n```\n\n✅ Good: a version is used.\n\n```dockerfile\nFROM alpine:3.20\n```\n", doc.body)
+	require.Equal(t, []Example{
+		{Good: false, Description: "the image isn't pinned.", Dockerfile: "FROM alpine:latest\n"},
+		{Good: true, Description: "a version is used.", Dockerfile: "FROM alpine:3.20\n"},
+	}, doc.examples)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "no front matter",
+			content:  "LatestTag\n\n## Output\n",
+			expected: "missing front matter",
+		},
+		{
+			name:     "unterminated front matter",
+			content:  "---\ntitle: LatestTag\n## Output\n",
+			expected: "unterminated front matter",
+		},
+		{
+			name:     "missing summary",
+			content:  "---\ntitle: LatestTag\ncategory: FROM\n---\n",
+			expected: "missing summary",
+		},
+		{
+			name:     "missing category",
+			content:  "---\ntitle: LatestTag\nsummary: Pin images\n---\n",
+			expected: "missing category",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDocument([]byte(tt.content))
+			require.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+// preempted are the rules that the parser preempts: it rejects every input
+// they would report as InvalidInstruction before any check runs
+var preempted = map[string]bool{
+	"CmdMissingCommand":         true,
+	"EntrypointMissingCommand":  true,
+	"EnvMissingKeyValue":        true,
+	"LabelMissingKeyValue":      true,
+	"OnbuildMissingInstruction": true,
+	"ShellMissingConfig":        true,
+}
+
+// TestExamples lints the examples of every rule, with the rule enabled even
+// when it is opt-in: the bad ones should report the rule and the good ones
+// should not
+func TestExamples(t *testing.T) {
+	enabled := true
+	for _, rule := range Rules() {
+		config := &parse.Config{Rules: map[string]parse.RuleConfig{rule.Code: {Enabled: &enabled}}}
+		for _, example := range rule.Examples {
+			t.Run(rule.Code, func(t *testing.T) {
+				result, err := parse.ParseDockerfileWithConfig(example.Dockerfile, config)
+				require.NoError(t, err)
+
+				code := rule.Code
+				if preempted[code] {
+					code = "InvalidInstruction"
+				}
+				reported := false
+				for _, finding := range result.Rules {
+					reported = reported || finding.Code == code
+				}
+				if example.Good {
+					require.False(t, reported, "the good example %q reports %s", example.Description, code)
+				} else {
+					require.True(t, reported, "the bad example %q doesn't report %s", example.Description, code)
+				}
+			})
+		}
+	}
+}
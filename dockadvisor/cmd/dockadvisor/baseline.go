@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/deckrun/dockadvisor/baseline"
+	"github.com/deckrun/dockadvisor/report"
+)
+
+// runBaseline runs the "baseline" subcommand and returns the exit code.
+// "baseline write" lints the Dockerfiles and records every finding in the
+// baseline file.
+func runBaseline(args []string, stdout io.Writer) int {
+	if len(args) == 0 || args[0] != "write" {
+		log.Println("Usage: dockadvisor baseline write [flags] [path|dir|glob ...]")
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet("baseline write", flag.ContinueOnError)
+	lint := newLintFlags(flags)
+	output := flags.String("o", baseline.FileName, "path of the baseline file to write")
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	paths, configs, build, code := lint.resolve(flags.Args())
+	if code != exitOK {
+		return code
+	}
+
+	files, code := lintFiles(paths, os.Stdin, *lint.stdinFilename, configs, build)
+	if code == exitUsage {
+		// Don't replace the baseline with a partial one
+		return code
+	}
+
+	known := baseline.New(filepath.Dir(*output))
+	findings := 0
+	for _, file := range files {
+		known.Add(file.Path, file.Result.Rules)
+		findings += len(file.Result.Rules)
+	}
+
+	out, err := os.Create(*output)
+	if err != nil {
+		log.Println("Error writing baseline:", err)
+		return exitUsage
+	}
+	if err := known.Write(out); err != nil {
+		out.Close()
+		log.Println("Error writing baseline:", err)
+		return exitUsage
+	}
+	if err := out.Close(); err != nil {
+		log.Println("Error writing baseline:", err)
+		return exitUsage
+	}
+
+	fmt.Fprintf(stdout, "Recorded %d findings from %d Dockerfiles in %s\n", findings, len(files), *output)
+	return code
+}
+
+// loadBaseline returns the baseline to apply: the file at path, or the
+// default baseline file in the working directory when path is empty and
+// that file exists. It returns nil when there is no baseline.
+func loadBaseline(path string) (*baseline.Baseline, error) {
+	if path == "" {
+		if _, err := os.Stat(baseline.FileName); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		path = baseline.FileName
+	}
+	return baseline.Load(path)
+}
+
+// applyBaseline removes the known findings from the results and returns
+// the number of findings removed
+func applyBaseline(known *baseline.Baseline, files []report.File) int {
+	if known == nil {
+		return 0
+	}
+
+	removed := 0
+	for _, file := range files {
+		removed += known.Filter(file.Path, file.Result)
+	}
+	return removed
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/baseline"
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBaseline(t *testing.T) {
+	setupDockerfileTree(t, "Dockerfile", "api/Dockerfile")
+	dir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("Dockerfile", []byte("FROM alpine\nWORKDIR app\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join("api", "Dockerfile"), []byte("FROM alpine\nMAINTAINER me\n"), 0o644))
+
+	var stdout bytes.Buffer
+	require.Equal(t, exitOK, runBaseline([]string{"write", "."}, &stdout))
+	require.Equal(t, "Recorded 2 findings from 2 Dockerfiles in "+baseline.FileName+"\n", stdout.String())
+
+	known, err := loadBaseline("")
+	require.NoError(t, err)
+	require.NotNil(t, known, "the default baseline file should be picked up")
+	require.Len(t, known.Findings, 2)
+
+	// A new finding is still reported once the baseline is applied
+	require.NoError(t, os.WriteFile("Dockerfile", []byte("# Application image\nFROM alpine\nWORKDIR app\nWORKDIR /app\nMAINTAINER me\n"), 0o644))
+	files, code := lintFiles([]string{"Dockerfile", filepath.Join(dir, "api", "Dockerfile")}, strings.NewReader(""), "", newConfigLoader(""), parse.LintInput{})
+	require.Equal(t, exitOK, code)
+	require.Equal(t, 2, applyBaseline(known, files))
+	require.Len(t, files[0].Result.Rules, 1)
+	require.Equal(t, "MaintainerDeprecated", files[0].Result.Rules[0].Code)
+	require.Empty(t, files[1].Result.Rules)
+}
+
+func TestRunBaselineLintFlags(t *testing.T) {
+	setupDockerfileTree(t, "Dockerfile")
+	require.NoError(t, os.WriteFile("Dockerfile", []byte("FROM alpine AS build\nWORKDIR app\nFROM alpine\nWORKDIR app\nUSER root\n"), 0o644))
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string // codes of the recorded findings
+	}{
+		{
+			name:     "every finding",
+			args:     []string{"write"},
+			expected: []string{"WorkdirRelativePath", "WorkdirRelativePath"},
+		},
+		{
+			name:     "preset",
+			args:     []string{"write", "--preset", "security"},
+			expected: []string{"FromUnpinnedImage", "FromUnpinnedImage", "UserRoot"},
+		},
+		{
+			name:     "target",
+			args:     []string{"write", "--target", "build"},
+			expected: []string{"WorkdirRelativePath"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			require.Equal(t, exitOK, runBaseline(tt.args, &stdout))
+
+			known, err := loadBaseline("")
+			require.NoError(t, err)
+			codes := []string{}
+			for _, finding := range known.Findings {
+				codes = append(codes, finding.Code)
+			}
+			require.ElementsMatch(t, tt.expected, codes)
+		})
+	}
+
+	var stdout bytes.Buffer
+	require.Equal(t, exitUsage, runBaseline([]string{"write", "--preset", "paranoid"}, &stdout))
+}
+
+func TestRunBaselineUsage(t *testing.T) {
+	var stdout bytes.Buffer
+	require.Equal(t, exitUsage, runBaseline(nil, &stdout))
+	require.Equal(t, exitUsage, runBaseline([]string{"read"}, &stdout))
+}
+
+func TestLoadBaselineMissing(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	known, err := loadBaseline("")
+	require.NoError(t, err)
+	require.Nil(t, known)
+
+	_, err = loadBaseline("missing.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
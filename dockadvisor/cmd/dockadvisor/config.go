@@ -0,0 +1,94 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/deckrun/dockadvisor/config"
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+// configLoader resolves the configuration of each Dockerfile. An explicit
+// --config file applies to every Dockerfile; otherwise the nearest
+// .dockadvisor.yaml in the Dockerfile's directory or its parents is used.
+// Either way, the file's overrides matching the Dockerfile's path are
+// applied.
+type configLoader struct {
+	explicit string
+
+	// preset replaces the configured preset when set, see --preset
+	preset string
+
+	// byDir caches the configuration file found for each directory
+	byDir map[string]*config.File
+	// byPath caches each loaded configuration file
+	byPath map[string]*config.File
+}
+
+func newConfigLoader(explicit string) *configLoader {
+	return &configLoader{
+		explicit: explicit,
+		byDir:    map[string]*config.File{},
+		byPath:   map[string]*config.File{},
+	}
+}
+
+// forFile returns the configuration profile of the Dockerfile at path, an
+// empty one when there is no configuration file
+func (l *configLoader) forFile(path string) (config.Profile, error) {
+	file, err := l.fileFor(path)
+	if err != nil {
+		return config.Profile{}, err
+	}
+
+	profile := file.Profile(path)
+	if l.preset != "" {
+		withPreset := parse.Config{}
+		if profile.Config != nil {
+			withPreset = *profile.Config
+		}
+		withPreset.Preset = l.preset
+		profile.Config = &withPreset
+	}
+	return profile, nil
+}
+
+// fileFor returns the configuration file that applies to the Dockerfile at
+// path, or nil when there is none
+func (l *configLoader) fileFor(path string) (*config.File, error) {
+	if l.explicit != "" {
+		return l.load(l.explicit)
+	}
+
+	dir := filepath.Dir(path)
+	if file, ok := l.byDir[dir]; ok {
+		return file, nil
+	}
+
+	configPath, err := config.Find(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded *config.File
+	if configPath != "" {
+		if loaded, err = l.load(configPath); err != nil {
+			return nil, err
+		}
+	}
+	l.byDir[dir] = loaded
+	return loaded, nil
+}
+
+// load reads a configuration file once
+func (l *configLoader) load(path string) (*config.File, error) {
+	if loaded, ok := l.byPath[path]; ok {
+		return loaded, nil
+	}
+
+	loaded, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	l.byPath[path] = loaded
+	return loaded, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/deckrun/dockadvisor/diff"
+	"github.com/deckrun/dockadvisor/report"
+)
+
+// loadChanges returns the changed lines read from the unified diff at
+// diffPath ("-" for stdin) and the path:start-end specs, or nil when neither
+// is given and every finding should be reported. The paths of the diff are
+// relative to root, the root of the repository, like those of git diff; the
+// paths of the specs are resolved against it.
+func loadChanges(diffPath string, specs []string, stdin io.Reader, root string) (diff.Changes, error) {
+	if diffPath == "" && len(specs) == 0 {
+		return nil, nil
+	}
+
+	changes := diff.Changes{}
+	if diffPath != "" {
+		r := stdin
+		if diffPath != stdinPath {
+			file, err := os.Open(diffPath)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			r = file
+		}
+
+		var err error
+		if changes, err = diff.Parse(r); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, spec := range specs {
+		if err := changes.AddSpec(root, spec); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// applyChanges removes the findings outside of the changed lines, whose
+// paths are relative to root, and returns the number of findings removed
+func applyChanges(changes diff.Changes, root string, files []report.File) int {
+	if changes == nil {
+		return 0
+	}
+
+	removed := 0
+	for _, file := range files {
+		removed += changes.Filter(root, file.Path, file.Result)
+	}
+	return removed
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/config"
+	"github.com/deckrun/dockadvisor/diff"
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/deckrun/dockadvisor/report"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadChanges(t *testing.T) {
+	changes, err := loadChanges("", nil, strings.NewReader(""), "")
+	require.NoError(t, err)
+	require.Nil(t, changes, "no diff means no filtering")
+
+	patch := "+++ b/Dockerfile\n@@ -1,0 +2 @@\n+WORKDIR app\n"
+	changes, err = loadChanges(stdinPath, []string{"other/Dockerfile:4-5"}, strings.NewReader(patch), "")
+	require.NoError(t, err)
+	require.Equal(t, diff.Changes{"Dockerfile": {{Start: 2, End: 2}}, "other/Dockerfile": {{Start: 4, End: 5}}}, changes)
+
+	diffPath := filepath.Join(t.TempDir(), "pr.diff")
+	require.NoError(t, os.WriteFile(diffPath, []byte(patch), 0o644))
+	changes, err = loadChanges(diffPath, nil, strings.NewReader(""), "")
+	require.NoError(t, err)
+	require.Equal(t, diff.Changes{"Dockerfile": {{Start: 2, End: 2}}}, changes)
+
+	_, err = loadChanges("", []string{"Dockerfile"}, strings.NewReader(""), "")
+	require.ErrorContains(t, err, "expected path:start-end")
+}
+
+func TestApplyChanges(t *testing.T) {
+	result, err := parse.ParseDockerfile("FROM alpine:3.20\nWORKDIR app\nMAINTAINER me\n")
+	require.NoError(t, err)
+	files := []report.File{{Path: "Dockerfile", Result: result}}
+
+	require.Zero(t, applyChanges(nil, "", files))
+	require.Equal(t, 1, applyChanges(diff.Changes{"Dockerfile": {{Start: 3, End: 3}}}, "", files))
+	require.Len(t, result.Rules, 1)
+	require.Equal(t, "MaintainerDeprecated", result.Rules[0].Code)
+	require.Equal(t, 90, result.Score, "the score is the one of the whole file")
+}
+
+func TestApplyChangesFromSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "docker"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docker", "Dockerfile"), []byte("FROM alpine:3.20\nWORKDIR app\nMAINTAINER me\nWORKDIR tmp\n"), 0o644))
+	t.Chdir(filepath.Join(root, "docker"))
+
+	// git diff paths are relative to the repository root, the Dockerfile
+	// and --changed-lines paths to the working directory
+	repoRoot, err := config.RepositoryRoot(".")
+	require.NoError(t, err)
+	patch := "+++ b/docker/Dockerfile\n@@ -2,0 +3 @@\n+MAINTAINER me\n"
+	changes, err := loadChanges(stdinPath, []string{"Dockerfile:4"}, strings.NewReader(patch), repoRoot)
+	require.NoError(t, err)
+	require.Equal(t, diff.Changes{"docker/Dockerfile": {{Start: 3, End: 4}}}, changes)
+
+	files, code := lintFiles([]string{"Dockerfile"}, strings.NewReader(""), "", newConfigLoader(""), parse.LintInput{})
+	require.Equal(t, exitOK, code)
+	require.Equal(t, 1, applyChanges(changes, repoRoot, files))
+
+	codes := []string{}
+	for _, rule := range files[0].Result.Rules {
+		codes = append(codes, rule.Code)
+	}
+	require.Equal(t, []string{"MaintainerDeprecated", "WorkdirRelativePath"}, codes)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/deckrun/dockadvisor/internal/pathmatch"
+)
+
+// discoverDockerfiles expands the given paths into a sorted, de-duplicated
+// list of Dockerfiles:
+//   - a file path is used as is, whatever its name
+//   - a directory is searched recursively for Dockerfile-like names
+//   - a glob pattern (with "**" support) selects the matching files, and
+//     matching directories are searched recursively
+//
+// A "-" argument stands for stdin and is passed through unchanged.
+//
+// Exclude patterns apply to everything found through directories and globs.
+// A pattern without a "/" is also matched against the base name, so
+// "*.dev.Dockerfile" excludes such files at any depth.
+func discoverDockerfiles(args, excludes []string) ([]string, error) {
+	for _, pattern := range excludes {
+		if err := pathmatch.Validate(pattern); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, arg := range args {
+		if arg == stdinPath {
+			found[arg] = true
+			continue
+		}
+		if pathmatch.HasMeta(arg) {
+			if err := pathmatch.Validate(filepath.ToSlash(arg)); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+			}
+			if err := expandGlob(arg, excludes, found); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("dockerfile not found at path: %s", arg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			if err := walkDockerfiles(arg, excludes, found); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		found[filepath.Clean(arg)] = true
+	}
+
+	if len(found) == 0 {
+		return nil, errors.New("no Dockerfiles found in " + strings.Join(args, ", "))
+	}
+
+	paths := make([]string, 0, len(found))
+	for p := range found {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// isDockerfileName reports whether a base name looks like a Dockerfile
+func isDockerfileName(name string) bool {
+	return name == "Dockerfile" ||
+		name == "Containerfile" ||
+		strings.HasPrefix(name, "Dockerfile.") ||
+		strings.HasSuffix(name, ".Dockerfile")
+}
+
+// isExcluded reports whether p matches one of the exclude patterns
+func isExcluded(p string, excludes []string) bool {
+	p = filepath.ToSlash(filepath.Clean(p))
+	for _, pattern := range excludes {
+		if pathmatch.Match(pattern, p) {
+			return true
+		}
+		if !strings.Contains(pattern, "/") && pathmatch.Match(pattern, path.Base(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkDockerfiles adds every Dockerfile below root to found. Hidden
+// directories such as .git are skipped.
+func walkDockerfiles(root string, excludes []string, found map[string]bool) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p != root && (strings.HasPrefix(d.Name(), ".") || isExcluded(p, excludes)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if isDockerfileName(d.Name()) && !isExcluded(p, excludes) {
+			found[filepath.Clean(p)] = true
+		}
+		return nil
+	})
+}
+
+// expandGlob adds the files matching pattern to found and searches the
+// matching directories for Dockerfiles. Hidden directories below the
+// pattern's fixed prefix are skipped.
+func expandGlob(pattern string, excludes []string, found map[string]bool) error {
+	pattern = path.Clean(filepath.ToSlash(pattern))
+
+	// Walk from the longest directory prefix without metacharacters
+	segments := strings.Split(pattern, "/")
+	var prefix []string
+	for _, segment := range segments[:len(segments)-1] {
+		if pathmatch.HasMeta(segment) {
+			break
+		}
+		prefix = append(prefix, segment)
+	}
+	root := "."
+	if len(prefix) != 0 {
+		root = strings.Join(prefix, "/")
+		if root == "" {
+			root = "/"
+		}
+	}
+
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	root = filepath.FromSlash(root)
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		slashPath := filepath.ToSlash(p)
+		if d.IsDir() {
+			if p != root && (strings.HasPrefix(d.Name(), ".") || isExcluded(p, excludes)) {
+				return filepath.SkipDir
+			}
+			if slashPath != "." && pathmatch.Match(pattern, slashPath) {
+				if err := walkDockerfiles(p, excludes, found); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if pathmatch.Match(pattern, slashPath) && !isExcluded(p, excludes) {
+			found[filepath.Clean(p)] = true
+		}
+		return nil
+	})
+}
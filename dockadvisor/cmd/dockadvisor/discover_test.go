@@ -0,0 +1,173 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupDockerfileTree creates the given files below a temporary directory
+// and changes into it
+func setupDockerfileTree(t *testing.T, files ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("FROM alpine\n"), 0o644))
+	}
+	t.Chdir(dir)
+}
+
+func TestDiscoverDockerfiles(t *testing.T) {
+	setupDockerfileTree(t,
+		"Dockerfile",
+		"README.md",
+		"api/Dockerfile",
+		"api/Dockerfile.dev",
+		"web/Containerfile",
+		"web/app.Dockerfile",
+		"web/vendor/Dockerfile",
+		"tools/build.docker",
+		".git/Dockerfile",
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		excludes []string
+		expected []string
+	}{
+		{
+			name:     "explicit file",
+			args:     []string{"Dockerfile"},
+			expected: []string{"Dockerfile"},
+		},
+		{
+			name:     "explicit file with any name",
+			args:     []string{"tools/build.docker"},
+			expected: []string{"tools/build.docker"},
+		},
+		{
+			name:     "directory is searched recursively",
+			args:     []string{"web"},
+			expected: []string{"web/Containerfile", "web/app.Dockerfile", "web/vendor/Dockerfile"},
+		},
+		{
+			name:     "current directory skips hidden directories",
+			args:     []string{"."},
+			expected: []string{"Dockerfile", "api/Dockerfile", "api/Dockerfile.dev", "web/Containerfile", "web/app.Dockerfile", "web/vendor/Dockerfile"},
+		},
+		{
+			name:     "glob pattern",
+			args:     []string{"*/Dockerfile"},
+			expected: []string{"api/Dockerfile"},
+		},
+		{
+			name:     "double star glob",
+			args:     []string{"**/Dockerfile"},
+			expected: []string{"Dockerfile", "api/Dockerfile", "web/vendor/Dockerfile"},
+		},
+		{
+			name:     "glob matching directories",
+			args:     []string{"w*"},
+			expected: []string{"web/Containerfile", "web/app.Dockerfile", "web/vendor/Dockerfile"},
+		},
+		{
+			name:     "exclude directory by name",
+			args:     []string{"."},
+			excludes: []string{"vendor"},
+			expected: []string{"Dockerfile", "api/Dockerfile", "api/Dockerfile.dev", "web/Containerfile", "web/app.Dockerfile"},
+		},
+		{
+			name:     "exclude base name pattern",
+			args:     []string{"."},
+			excludes: []string{"Dockerfile.*", "*.Dockerfile"},
+			expected: []string{"Dockerfile", "api/Dockerfile", "web/Containerfile", "web/vendor/Dockerfile"},
+		},
+		{
+			name:     "exclude path pattern",
+			args:     []string{"**/Dockerfile"},
+			excludes: []string{"web/**"},
+			expected: []string{"Dockerfile", "api/Dockerfile"},
+		},
+		{
+			name:     "excludes do not apply to explicit files",
+			args:     []string{"api/Dockerfile.dev"},
+			excludes: []string{"Dockerfile.*"},
+			expected: []string{"api/Dockerfile.dev"},
+		},
+		{
+			name:     "stdin is passed through",
+			args:     []string{"-", "api"},
+			expected: []string{"-", "api/Dockerfile", "api/Dockerfile.dev"},
+		},
+		{
+			name:     "duplicates are removed",
+			args:     []string{"api/Dockerfile", "api", "**/Dockerfile"},
+			expected: []string{"Dockerfile", "api/Dockerfile", "api/Dockerfile.dev", "web/vendor/Dockerfile"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := discoverDockerfiles(tt.args, tt.excludes)
+			require.NoError(t, err)
+
+			expected := make([]string, len(tt.expected))
+			for i, path := range tt.expected {
+				expected[i] = filepath.FromSlash(path)
+			}
+			require.Equal(t, expected, paths)
+		})
+	}
+}
+
+func TestDiscoverDockerfilesErrors(t *testing.T) {
+	setupDockerfileTree(t, "api/Dockerfile", "README.md")
+
+	tests := []struct {
+		name     string
+		args     []string
+		excludes []string
+		errMsg   string
+	}{
+		{
+			name:   "missing file",
+			args:   []string{"Dockerfile"},
+			errMsg: "dockerfile not found at path: Dockerfile",
+		},
+		{
+			name:   "glob without matches",
+			args:   []string{"services/**/Dockerfile"},
+			errMsg: "no Dockerfiles found in services/**/Dockerfile",
+		},
+		{
+			name:     "everything excluded",
+			args:     []string{"."},
+			excludes: []string{"api"},
+			errMsg:   "no Dockerfiles found in .",
+		},
+		{
+			name:   "invalid pattern",
+			args:   []string{"[api/Dockerfile"},
+			errMsg: "invalid pattern",
+		},
+		{
+			name:     "invalid exclude pattern",
+			args:     []string{"."},
+			excludes: []string{"[api"},
+			errMsg:   "invalid exclude pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := discoverDockerfiles(tt.args, tt.excludes)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
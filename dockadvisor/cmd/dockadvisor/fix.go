@@ -0,0 +1,70 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/deckrun/dockadvisor/diff"
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/deckrun/dockadvisor/report"
+)
+
+// fixSummary counts what applying the fixes of the findings did
+type fixSummary struct {
+	Fixed   int // findings fixed
+	Skipped int // fixable findings left for a later run, their fix overlapping another one
+	Files   int // Dockerfiles changed
+}
+
+// fixFiles applies the fixes of the findings of files. With dryRun the
+// changes are written to w as a unified diff; otherwise each changed
+// Dockerfile is rewritten in place.
+func fixFiles(files []report.File, dryRun bool, w io.Writer) (fixSummary, error) {
+	var summary fixSummary
+	for _, file := range files {
+		fixable := 0
+		for _, rule := range file.Result.Rules {
+			if len(rule.Fix) > 0 {
+				fixable++
+			}
+		}
+		if fixable == 0 {
+			continue
+		}
+
+		content, fixed := parse.ApplyFixes(string(file.Content), file.Result.Rules)
+		summary.Fixed += fixed
+		summary.Skipped += fixable - fixed
+		if content == string(file.Content) {
+			continue
+		}
+		summary.Files++
+
+		if dryRun {
+			if err := diff.WriteUnified(w, file.Path, string(file.Content), content); err != nil {
+				return summary, err
+			}
+			continue
+		}
+
+		// The file exists, so it keeps its permissions
+		if err := os.WriteFile(file.Path, []byte(content), 0o644); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// logFixes logs what applying the fixes did, or would do with dryRun
+func logFixes(summary fixSummary, dryRun bool) {
+	switch {
+	case dryRun:
+		log.Printf("Fix: %d findings can be fixed in %d Dockerfiles", summary.Fixed, summary.Files)
+	case summary.Fixed > 0:
+		log.Printf("Fix: %d findings fixed in %d Dockerfiles", summary.Fixed, summary.Files)
+	}
+	if summary.Skipped > 0 {
+		log.Printf("Fix: %d findings have fixes overlapping others, run --fix again to apply them", summary.Skipped)
+	}
+}
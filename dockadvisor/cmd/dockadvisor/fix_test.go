@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/deckrun/dockadvisor/report"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	content := "FROM alpine:3.20\nmaintainer me\nEXPOSE 80/TCP\nWORKDIR app\n"
+	require.NoError(t, os.WriteFile("Dockerfile", []byte(content), 0o600))
+	require.NoError(t, os.WriteFile("clean.Dockerfile", []byte("FROM alpine:3.20\n"), 0o644))
+	paths := []string{"Dockerfile", "clean.Dockerfile"}
+
+	lint := func() []report.File {
+		files, code := lintFiles(paths, strings.NewReader(""), "", newConfigLoader(""), parse.LintInput{})
+		require.Equal(t, exitOK, code)
+		return files
+	}
+
+	t.Run("dry run writes a diff", func(t *testing.T) {
+		var stdout strings.Builder
+		summary, err := fixFiles(lint(), true, &stdout)
+		require.NoError(t, err)
+		require.Equal(t, fixSummary{Fixed: 2, Skipped: 1, Files: 1}, summary)
+		require.Equal(t, `--- a/Dockerfile
++++ b/Dockerfile
+@@ -1,4 +1,4 @@
+ FROM alpine:3.20
+-maintainer me
+-EXPOSE 80/TCP
++MAINTAINER me
++EXPOSE 80/tcp
+ WORKDIR app
+`, stdout.String())
+
+		written, err := os.ReadFile("Dockerfile")
+		require.NoError(t, err)
+		require.Equal(t, content, string(written), "a dry run changes no file")
+	})
+
+	t.Run("fix rewrites the Dockerfiles", func(t *testing.T) {
+		summary, err := fixFiles(lint(), false, nil)
+		require.NoError(t, err)
+		require.Equal(t, fixSummary{Fixed: 2, Skipped: 1, Files: 1}, summary)
+
+		// The MAINTAINER fix overlapped the casing one and is applied by
+		// the next run
+		summary, err = fixFiles(lint(), false, nil)
+		require.NoError(t, err)
+		require.Equal(t, fixSummary{Fixed: 1, Files: 1}, summary)
+
+		written, err := os.ReadFile("Dockerfile")
+		require.NoError(t, err)
+		require.Equal(t, "FROM alpine:3.20\nLABEL org.opencontainers.image.authors=\"me\"\nEXPOSE 80/tcp\nWORKDIR app\n", string(written))
+		info, err := os.Stat("Dockerfile")
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "the file keeps its permissions")
+
+		files := lint()
+		require.Len(t, files[0].Result.Rules, 1)
+		require.Equal(t, "WorkdirRelativePath", files[0].Result.Rules[0].Code, "only the findings without a fix are left")
+	})
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/deckrun/dockadvisor/report"
+)
+
+// githubActionInputs holds the inputs declared in the action's action.yml
+type githubActionInputs struct {
+	dockerfiles   []string
+	excludes      []string
+	config        string
+	baseline      string
+	failOnError   bool
+	failOnWarning bool
+	minimumScore  int
+
+	// minimumScoreSet is true when minimum-score was given; otherwise the
+	// min-score of each Dockerfile's configuration applies
+	minimumScoreSet bool
+}
+
+// runGitHubAction lints the Dockerfiles configured through the INPUT_*
+// environment variables, emits annotations and step outputs, and returns
+// the process exit code. With several Dockerfiles the score output is the
+// lowest score and the issue counts are totals.
+func runGitHubAction(stdout io.Writer) int {
+	inputs, err := readGitHubActionInputs()
+	if err != nil {
+		fmt.Fprintf(stdout, "::error::%v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, "============================================")
+	fmt.Fprintln(stdout, "Dockadvisor - Dockerfile Linter")
+	fmt.Fprintln(stdout, "============================================")
+
+	paths, err := discoverDockerfiles(inputs.dockerfiles, inputs.excludes)
+	if err != nil {
+		fmt.Fprintf(stdout, "::error::%v\n", err)
+		return 1
+	}
+
+	known, err := loadBaseline(inputs.baseline)
+	if err != nil {
+		fmt.Fprintf(stdout, "::error::Failed to load baseline: %v\n", err)
+		return 1
+	}
+
+	configs := newConfigLoader(inputs.config)
+	files := make([]report.File, 0, len(paths))
+	minimumScores := make([]int, 0, len(paths))
+	var rules []parse.Rule
+	for _, path := range paths {
+		fmt.Fprintf(stdout, "Analyzing: %s\n", path)
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stdout, "::error::Failed to read %s: %v\n", path, err)
+			return 1
+		}
+
+		profile, err := configs.forFile(path)
+		if err != nil {
+			fmt.Fprintf(stdout, "::error::Failed to load configuration for %s: %v\n", path, err)
+			return 1
+		}
+
+		lintResult, err := parse.Lint(context.Background(), parse.LintInput{Filename: path, Content: string(content)}, parse.Options{Config: profile.Config})
+		if err != nil {
+			fmt.Fprintf(stdout, "::error file=%s::%v\n", path, err)
+			return 1
+		}
+
+		result := lintResult.Result
+		file := report.File{Path: path, Result: result, Content: content}
+		if removed := applyBaseline(known, []report.File{file}); removed != 0 {
+			fmt.Fprintf(stdout, "Baseline: %d known findings hidden\n", removed)
+		}
+
+		files = append(files, file)
+		minimumScores = append(minimumScores, profile.MinScore)
+		rules = append(rules, result.Rules...)
+	}
+	fmt.Fprintln(stdout, "")
+
+	if err := report.WriteGitHub(stdout, files); err != nil {
+		fmt.Fprintf(stdout, "::error::Failed to write annotations: %v\n", err)
+		return 1
+	}
+
+	// Fatal rules stop the build entirely, so they are counted as errors
+	summary := report.Summarize(rules)
+	score := report.LowestScore(files)
+	errors := summary.Fatal + summary.Error
+	warnings := summary.Warning
+
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "============================================")
+	fmt.Fprintln(stdout, "Summary")
+	fmt.Fprintln(stdout, "============================================")
+	if len(files) > 1 {
+		fmt.Fprintf(stdout, "Files: %d\n", len(files))
+	}
+	fmt.Fprintf(stdout, "Score: %d/100\n", score)
+	fmt.Fprintf(stdout, "Total Issues: %d\n", summary.Problems())
+	fmt.Fprintf(stdout, "Errors: %d\n", errors)
+	fmt.Fprintf(stdout, "Warnings: %d\n", warnings)
+	if suggestions := summary.Info + summary.Hint; suggestions != 0 {
+		fmt.Fprintf(stdout, "Suggestions: %d\n", suggestions)
+	}
+	fmt.Fprintln(stdout, "")
+
+	var failures []string
+	if inputs.failOnError && errors > 0 {
+		failures = append(failures, fmt.Sprintf("found %d error(s)", errors))
+	}
+	if inputs.failOnWarning && warnings > 0 {
+		failures = append(failures, fmt.Sprintf("found %d warning(s)", warnings))
+	}
+	if inputs.minimumScoreSet {
+		if score < inputs.minimumScore {
+			failures = append(failures, fmt.Sprintf("score %d is below minimum threshold of %d", score, inputs.minimumScore))
+		}
+	} else {
+		for i, file := range files {
+			if file.Result.Score < minimumScores[i] {
+				failures = append(failures, fmt.Sprintf("%s score %d is below minimum threshold of %d", file.Path, file.Result.Score, minimumScores[i]))
+			}
+		}
+	}
+
+	outcome := "passed"
+	if len(failures) != 0 {
+		outcome = "failed"
+	}
+
+	outputs := []string{
+		"score=" + strconv.Itoa(score),
+		"errors=" + strconv.Itoa(errors),
+		"warnings=" + strconv.Itoa(warnings),
+		"result=" + outcome,
+	}
+	if err := writeGitHubOutputs(outputs); err != nil {
+		fmt.Fprintf(stdout, "::error::Failed to write step outputs: %v\n", err)
+		return 1
+	}
+
+	if len(failures) != 0 {
+		fmt.Fprintf(stdout, "::error::Action failed: %s\n", strings.Join(failures, " and "))
+		return 1
+	}
+
+	fmt.Fprintln(stdout, "✓ Dockerfile analysis passed!")
+	return 0
+}
+
+// readGitHubActionInputs reads and validates the action inputs
+func readGitHubActionInputs() (githubActionInputs, error) {
+	// Both inputs take whitespace-separated lists so a multi-line YAML
+	// value can name several paths, directories or glob patterns
+	inputs := githubActionInputs{
+		dockerfiles: strings.Fields(getGitHubInput("dockerfile")),
+		excludes:    strings.Fields(getGitHubInput("exclude")),
+		config:      getGitHubInput("config"),
+		baseline:    getGitHubInput("baseline"),
+	}
+	if len(inputs.dockerfiles) == 0 {
+		inputs.dockerfiles = []string{"Dockerfile"}
+	}
+
+	var err error
+	if inputs.failOnError, err = parseGitHubBool("fail-on-error"); err != nil {
+		return inputs, err
+	}
+	if inputs.failOnWarning, err = parseGitHubBool("fail-on-warning"); err != nil {
+		return inputs, err
+	}
+
+	if minimumScore := getGitHubInput("minimum-score"); minimumScore != "" {
+		inputs.minimumScoreSet = true
+		inputs.minimumScore, err = strconv.Atoi(minimumScore)
+		if err != nil || inputs.minimumScore < 0 || inputs.minimumScore > 100 {
+			return inputs, fmt.Errorf("input 'minimum-score' must be a number between 0 and 100, got %q", minimumScore)
+		}
+	}
+
+	return inputs, nil
+}
+
+// getGitHubInput returns the value of an action input. The runner exports
+// inputs as INPUT_<NAME> with the name upper-cased and hyphens preserved;
+// the underscore spelling is accepted too since most shells can't set the former.
+func getGitHubInput(name string) string {
+	key := "INPUT_" + strings.ToUpper(name)
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return strings.TrimSpace(os.Getenv(strings.ReplaceAll(key, "-", "_")))
+}
+
+// parseGitHubBool parses a boolean action input, treating an empty value as false
+func parseGitHubBool(name string) (bool, error) {
+	value := getGitHubInput(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("input '%s' must be true or false, got %q", name, value)
+	}
+	return parsed, nil
+}
+
+// writeGitHubOutputs appends name=value pairs to the $GITHUB_OUTPUT file.
+// Outside of a workflow run the variable is unset and nothing is written.
+func writeGitHubOutputs(outputs []string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	for _, output := range outputs {
+		if _, err := fmt.Fprintln(file, output); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupGitHubAction writes a Dockerfile and returns its path and the
+// $GITHUB_OUTPUT file path
+func setupGitHubAction(t *testing.T, dockerfile string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, os.WriteFile(path, []byte(dockerfile), 0o644))
+
+	outputPath := filepath.Join(dir, "github_output")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+	t.Setenv("INPUT_DOCKERFILE", path)
+	t.Setenv("INPUT_EXCLUDE", "")
+	t.Setenv("INPUT_CONFIG", "")
+	t.Setenv("INPUT_BASELINE", "")
+	t.Setenv("INPUT_FAIL-ON-ERROR", "")
+	t.Setenv("INPUT_FAIL-ON-WARNING", "")
+	t.Setenv("INPUT_MINIMUM-SCORE", "")
+	return path, outputPath
+}
+
+func TestRunGitHubAction(t *testing.T) {
+	tests := []struct {
+		name            string
+		dockerfile      string
+		inputs          map[string]string
+		expectedCode    int
+		expectedOutputs string
+		expectedLines   []string
+	}{
+		{
+			name:            "clean dockerfile passes",
+			dockerfile:      "FROM alpine:3.20\nWORKDIR /app\nCMD [\"sh\"]\n",
+			expectedCode:    0,
+			expectedOutputs: "score=100\nerrors=0\nwarnings=0\nresult=passed\n",
+		},
+		{
+			name:            "exact counts and annotation levels",
+			dockerfile:      "FROM alpine:3.20\nWORKDIR app\nEXPOSE 80:80\n",
+			expectedCode:    0,
+			expectedOutputs: "score=80\nerrors=1\nwarnings=1\nresult=passed\n",
+			expectedLines: []string{
+				"::warning file=",
+				"title=WorkdirRelativePath::",
+				"::error file=",
+				"title=ExposeInvalidFormat::",
+			},
+		},
+		{
+			name:            "fatal rules are errors and fail on error",
+			dockerfile:      "FROM alpine:3.20\nFOOBAR baz\n",
+			inputs:          map[string]string{"INPUT_FAIL-ON-ERROR": "true"},
+			expectedCode:    1,
+			expectedOutputs: "score=0\nerrors=1\nwarnings=0\nresult=failed\n",
+			expectedLines:   []string{"title=UnrecognizedInstruction::", "::error::Action failed: found 1 error(s)"},
+		},
+		{
+			name:            "fail on warning with underscore input name",
+			dockerfile:      "FROM alpine:3.20\nWORKDIR app\n",
+			inputs:          map[string]string{"INPUT_FAIL_ON_WARNING": "true"},
+			expectedCode:    1,
+			expectedOutputs: "score=95\nerrors=0\nwarnings=1\nresult=failed\n",
+			expectedLines:   []string{"::error::Action failed: found 1 warning(s)"},
+		},
+		{
+			name:            "minimum score",
+			dockerfile:      "FROM alpine:3.20\nWORKDIR app\n",
+			inputs:          map[string]string{"INPUT_MINIMUM-SCORE": "96"},
+			expectedCode:    1,
+			expectedOutputs: "score=95\nerrors=0\nwarnings=1\nresult=failed\n",
+			expectedLines:   []string{"score 95 is below minimum threshold of 96"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, outputPath := setupGitHubAction(t, tt.dockerfile)
+			for key, value := range tt.inputs {
+				t.Setenv(key, value)
+			}
+
+			var stdout bytes.Buffer
+			code := runGitHubAction(&stdout)
+			require.Equal(t, tt.expectedCode, code, stdout.String())
+
+			outputs, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedOutputs, string(outputs))
+
+			for _, line := range tt.expectedLines {
+				require.Contains(t, stdout.String(), line)
+			}
+		})
+	}
+}
+
+func TestRunGitHubActionMissingDockerfile(t *testing.T) {
+	setupGitHubAction(t, "FROM alpine\n")
+	t.Setenv("INPUT_DOCKERFILE", filepath.Join(t.TempDir(), "missing", "Dockerfile"))
+
+	var stdout bytes.Buffer
+	require.Equal(t, 1, runGitHubAction(&stdout))
+	require.Contains(t, stdout.String(), "::error::dockerfile not found at path:")
+}
+
+func TestRunGitHubActionMultipleDockerfiles(t *testing.T) {
+	path, outputPath := setupGitHubAction(t, "FROM alpine:3.20\nWORKDIR /app\n")
+	dir := filepath.Dir(path)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "api"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api", "Dockerfile"), []byte("FROM alpine:3.20\nWORKDIR app\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "legacy"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "legacy", "Dockerfile"), []byte("FROM alpine:3.20\nFOOBAR baz\n"), 0o644))
+
+	t.Setenv("INPUT_DOCKERFILE", path+"\n"+dir)
+	t.Setenv("INPUT_EXCLUDE", "legacy")
+
+	var stdout bytes.Buffer
+	require.Equal(t, 0, runGitHubAction(&stdout))
+	require.Contains(t, stdout.String(), "Files: 2")
+	require.NotContains(t, stdout.String(), "legacy")
+
+	outputs, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	require.Equal(t, "score=95\nerrors=0\nwarnings=1\nresult=passed\n", string(outputs))
+}
+
+func TestRunGitHubActionConfig(t *testing.T) {
+	path, outputPath := setupGitHubAction(t, "FROM alpine:3.20\nWORKDIR app\n")
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), ".dockadvisor.yaml"),
+		[]byte("rules:\n  WorkdirRelativePath:\n    severity: error\n"), 0o644))
+	t.Setenv("INPUT_FAIL-ON-ERROR", "true")
+
+	var stdout bytes.Buffer
+	require.Equal(t, 1, runGitHubAction(&stdout))
+	require.Contains(t, stdout.String(), "::error file="+path+",line=2")
+
+	outputs, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	require.Equal(t, "score=85\nerrors=1\nwarnings=0\nresult=failed\n", string(outputs))
+
+	t.Run("invalid config input", func(t *testing.T) {
+		t.Setenv("INPUT_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		var stdout bytes.Buffer
+		require.Equal(t, 1, runGitHubAction(&stdout))
+		require.Contains(t, stdout.String(), "::error::Failed to load configuration for "+path)
+	})
+}
+
+func TestRunGitHubActionConfigMinScore(t *testing.T) {
+	path, _ := setupGitHubAction(t, "FROM alpine:3.20\nWORKDIR app\n")
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), ".dockadvisor.yaml"),
+		[]byte("min-score: 100\n"), 0o644))
+
+	var stdout bytes.Buffer
+	require.Equal(t, 1, runGitHubAction(&stdout))
+	require.Contains(t, stdout.String(), "::error::Action failed: "+path+" score 95 is below minimum threshold of 100")
+
+	t.Run("minimum-score input takes precedence", func(t *testing.T) {
+		t.Setenv("INPUT_MINIMUM-SCORE", "90")
+
+		var stdout bytes.Buffer
+		require.Equal(t, 0, runGitHubAction(&stdout))
+	})
+}
+
+func TestRunGitHubActionInvalidInput(t *testing.T) {
+	setupGitHubAction(t, "FROM alpine\n")
+	t.Setenv("INPUT_MINIMUM-SCORE", "high")
+
+	var stdout bytes.Buffer
+	require.Equal(t, 1, runGitHubAction(&stdout))
+	require.Contains(t, stdout.String(), "::error::input 'minimum-score' must be a number between 0 and 100")
+}
@@ -1,36 +1,473 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"slices"
+	"sort"
+	"strings"
 
+	"github.com/deckrun/dockadvisor/baseline"
+	"github.com/deckrun/dockadvisor/config"
 	"github.com/deckrun/dockadvisor/parse"
+	"github.com/deckrun/dockadvisor/report"
 )
 
+// Exit codes of the CLI
+const (
+	exitOK       = 0 // no findings at or above the thresholds
+	exitFindings = 1 // findings exceeded --fail-on or --min-score, or --fix-dry-run has fixes
+	exitUsage    = 2 // invalid flags or arguments, or a file couldn't be read or written
+	exitParse    = 3 // a Dockerfile couldn't be parsed
+)
+
+// stdinPath is the -f value that reads the Dockerfile from stdin
+const stdinPath = "-"
+
+// failOnLevels ranks the --fail-on and --min-severity values. A rule fails
+// the run, or is reported, when the rank of its severity is at least the
+// rank of the threshold.
+var failOnLevels = map[string]int{
+	"none":                        0,
+	string(parse.SeverityHint):    1,
+	string(parse.SeverityInfo):    2,
+	string(parse.SeverityWarning): 3,
+	string(parse.SeverityError):   4,
+	string(parse.SeverityFatal):   5,
+}
+
+// reporters maps the machine-readable --format values to their writers
+var reporters = map[string]func(io.Writer, []report.File) error{
+	"json":       report.WriteJSON,
+	"sarif":      report.WriteSARIF,
+	"junit":      report.WriteJUnit,
+	"checkstyle": report.WriteCheckstyle,
+	"gitlab":     report.WriteGitLab,
+	"github":     report.WriteGitHub,
+}
+
+// stringList is a flag.Value collecting every occurrence of a repeatable flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// buildInput returns the build settings Dockerfiles are linted for. Like
+// docker build, a --build-arg without a value takes it from the environment
+// and is left out when the variable isn't set.
+func buildInput(buildArgs []string, target, platform string) parse.LintInput {
+	input := parse.LintInput{Target: target, Platform: platform}
+	for _, arg := range buildArgs {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			if value, ok = os.LookupEnv(name); !ok {
+				continue
+			}
+		}
+		if input.BuildArgs == nil {
+			input.BuildArgs = map[string]string{}
+		}
+		input.BuildArgs[name] = value
+	}
+	return input
+}
+
+// lintFlags are the flags choosing the Dockerfiles to lint and what they
+// are linted with. The lint path and "baseline write" share them, so a
+// baseline records what a run with the same flags reports.
+type lintFlags struct {
+	filePaths     stringList
+	excludes      stringList
+	stdinFilename *string
+	configPath    *string
+	preset        *string
+	buildArgs     stringList
+	target        *string
+	platform      *string
+}
+
+// newLintFlags defines the lint flags on flags
+func newLintFlags(flags *flag.FlagSet) *lintFlags {
+	f := &lintFlags{}
+	flags.Var(&f.filePaths, "f", "path to a Dockerfile, directory or glob pattern, or - for stdin; repeatable (default \"Dockerfile\")")
+	f.stdinFilename = flags.String("stdin-filename", "<stdin>", "path reported for the Dockerfile read from stdin")
+	flags.Var(&f.excludes, "exclude", "glob pattern of paths to skip while searching directories and globs; repeatable")
+	f.configPath = flags.String("config", "", "path to a configuration file (default: the nearest "+config.FileName+" above each Dockerfile)")
+	f.preset = flags.String("preset", "", "built-in rule preset, replacing the configured one: "+strings.Join(parse.Presets(), ", "))
+	flags.Var(&f.buildArgs, "build-arg", "KEY=VALUE build argument of the build being linted, or KEY to read it from the environment; repeatable")
+	f.target = flags.String("target", "", "stage being built; findings in stages it doesn't depend on are hidden")
+	f.platform = flags.String("platform", "", "target platform of the build, e.g. linux/arm64")
+	return f
+}
+
+// resolve returns the Dockerfiles to lint, given the flags and the
+// positional arguments args, with the configuration loader and the build
+// settings to lint them with. Problems are logged and reported as exitUsage.
+func (f *lintFlags) resolve(args []string) ([]string, *configLoader, parse.LintInput, int) {
+	if *f.preset != "" {
+		if _, err := parse.Preset(*f.preset); err != nil {
+			log.Println("Invalid --preset:", err)
+			return nil, nil, parse.LintInput{}, exitUsage
+		}
+	}
+
+	targets := append(f.filePaths, args...)
+	if len(targets) == 0 {
+		targets = []string{"Dockerfile"}
+	}
+
+	paths, err := discoverDockerfiles(targets, f.excludes)
+	if err != nil {
+		log.Println("Error finding Dockerfiles:", err)
+		return nil, nil, parse.LintInput{}, exitUsage
+	}
+
+	configs := newConfigLoader(*f.configPath)
+	configs.preset = *f.preset
+	if *f.configPath != "" {
+		if _, err := configs.load(*f.configPath); err != nil {
+			log.Println("Error loading configuration:", err)
+			return nil, nil, parse.LintInput{}, exitUsage
+		}
+	}
+
+	return paths, configs, buildInput(f.buildArgs, *f.target, *f.platform), exitOK
+}
+
+// formatNames lists every accepted --format value
+func formatNames() string {
+	names := []string{"text"}
+	for name := range reporters {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return strings.Join(names, ", ")
+}
+
 func main() {
-	filePath := flag.String("f", "Dockerfile", "path to Dockerfile")
+	log.SetFlags(0)
+
+	if len(os.Args) > 1 && os.Args[1] == "github-action" {
+		os.Exit(runGitHubAction(os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "baseline" {
+		os.Exit(runBaseline(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "rules" {
+		os.Exit(runRules(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "explain" {
+		os.Exit(runExplain(os.Args[2:], os.Stdout))
+	}
+
+	lint := newLintFlags(flag.CommandLine)
+	format := flag.String("format", "text", "output format: "+formatNames())
+	failOn := flag.String("fail-on", "fatal", "lowest severity that makes the run fail: fatal, error, warning, info, hint or none")
+	minSeverity := flag.String("min-severity", "hint", "lowest severity to report: fatal, error, warning, info or hint")
+	minScore := flag.Int("min-score", 0, "fail when a Dockerfile scores below this value (0-100); overrides the configured min-score")
+	showSuppressed := flag.Bool("show-suppressed", false, "include rules silenced by \"# dockadvisor ignore=...\" comments in the report")
+	baselinePath := flag.String("baseline", "", "path to a baseline file of known findings to hide (default: "+baseline.FileName+" when it exists)")
+	noBaseline := flag.Bool("no-baseline", false, "report every finding, even those in the baseline")
+	diffPath := flag.String("diff", "", "unified diff file, or - for stdin; only findings on the lines it adds or changes are reported")
+	var changedLines stringList
+	flag.Var(&changedLines, "changed-lines", "path:start-end range of changed lines; only findings on changed lines are reported; repeatable")
+	listPreset := flag.String("list-preset", "", "print the rule codes and severities a preset turns on and exit")
+	fix := flag.Bool("fix", false, "apply the automatic fixes of the reported findings to the Dockerfiles, then report what is left")
+	fixDryRun := flag.Bool("fix-dry-run", false, "print the automatic fixes of the reported findings as a unified diff instead of a report, changing no file, and exit with 1 if there are any")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		io.WriteString(out, "Usage: dockadvisor [flags] [path|dir|glob ...]\n       dockadvisor --fix|--fix-dry-run [flags] [path|dir|glob ...]\n       dockadvisor baseline write [flags] [path|dir|glob ...]\n       dockadvisor --list-preset NAME\n       dockadvisor rules [--format text|json]\n       dockadvisor explain <Code>\n       dockadvisor github-action\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	content, err := os.ReadFile(*filePath)
-	if err != nil {
-		log.Fatalf("Error reading %s: %v", *filePath, err)
+	if *listPreset != "" {
+		os.Exit(printPreset(os.Stdout, *listPreset))
 	}
 
-	result, err := parse.ParseDockerfile(string(content))
+	writeReport, ok := reporters[*format]
+	if !ok && *format != "text" {
+		log.Printf("Unknown output format %q, expected one of: %s", *format, formatNames())
+		os.Exit(exitUsage)
+	}
+	if _, ok := failOnLevels[*failOn]; !ok {
+		log.Printf("Unknown --fail-on value %q, expected one of: fatal, error, warning, info, hint, none", *failOn)
+		os.Exit(exitUsage)
+	}
+	if rank := failOnLevels[*minSeverity]; rank == 0 {
+		log.Printf("Unknown --min-severity value %q, expected one of: fatal, error, warning, info, hint", *minSeverity)
+		os.Exit(exitUsage)
+	}
+	if *minScore < 0 || *minScore > 100 {
+		log.Printf("--min-score must be between 0 and 100, got %d", *minScore)
+		os.Exit(exitUsage)
+	}
+	paths, configs, build, code := lint.resolve(flag.Args())
+	if code != exitOK {
+		os.Exit(code)
+	}
+
+	if *fix && *fixDryRun {
+		log.Println("--fix and --fix-dry-run can't be combined")
+		os.Exit(exitUsage)
+	}
+	if *fix && slices.Contains(paths, stdinPath) {
+		log.Println("--fix can't write the Dockerfile read from stdin, use --fix-dry-run")
+		os.Exit(exitUsage)
+	}
+	if *diffPath == stdinPath && slices.Contains(paths, stdinPath) {
+		log.Println("--diff - and -f - can't both read stdin")
+		os.Exit(exitUsage)
+	}
+	// Diff paths are relative to the root of the repository, wherever the
+	// CLI is run from
+	root, err := config.RepositoryRoot(".")
+	if err != nil {
+		log.Println("Error finding the repository root:", err)
+		os.Exit(exitUsage)
+	}
+	changes, err := loadChanges(*diffPath, changedLines, os.Stdin, root)
 	if err != nil {
-		log.Fatal("Error parsing Dockerfile:", err)
+		log.Println("Error loading changed lines:", err)
+		os.Exit(exitUsage)
+	}
+
+	var known *baseline.Baseline
+	if !*noBaseline {
+		if known, err = loadBaseline(*baselinePath); err != nil {
+			log.Println("Error loading baseline:", err)
+			os.Exit(exitUsage)
+		}
 	}
 
-	log.Println("Rules:")
-	log.Println("------")
-	for _, rule := range result.Rules {
-		if rule.StartLine == rule.EndLine {
-			log.Printf("Line %d: [%s] %s\n", rule.StartLine, rule.Code, rule.Description)
+	// filter leaves out the known findings, those outside of the diff and
+	// the suppressed ones, logging how many the baseline and the diff hid
+	// when logHidden is set. --min-severity is applied separately, after
+	// the thresholds are checked.
+	filter := func(files []report.File, logHidden bool) {
+		if removed := applyBaseline(known, files); removed != 0 && logHidden {
+			log.Printf("Baseline: %d known findings hidden", removed)
+		}
+		if removed := applyChanges(changes, root, files); removed != 0 && logHidden {
+			log.Printf("Diff: %d findings outside the changed lines hidden", removed)
+		}
+		if !*showSuppressed {
+			hideSuppressed(files)
+		}
+	}
+
+	files, code := lintFiles(paths, os.Stdin, *lint.stdinFilename, configs, build)
+	if *fix || *fixDryRun {
+		// Only the reported findings are fixed
+		filter(files, false)
+		hideBelow(files, failOnLevels[*minSeverity])
+		summary, err := fixFiles(files, *fixDryRun, os.Stdout)
+		if err != nil {
+			log.Println("Error fixing Dockerfiles:", err)
+			os.Exit(exitUsage)
+		}
+		logFixes(summary, *fixDryRun)
+
+		if *fixDryRun {
+			if code == exitOK && summary.Files > 0 {
+				code = exitFindings
+			}
+			os.Exit(code)
+		}
+
+		// Report what is left
+		files, code = lintFiles(paths, os.Stdin, *lint.stdinFilename, configs, build)
+	}
+	filter(files, true)
+
+	// An explicit --min-score applies to every Dockerfile, otherwise each
+	// one is held to the minimum of its configuration profile
+	minScoreSet := false
+	flag.Visit(func(f *flag.Flag) {
+		minScoreSet = minScoreSet || f.Name == "min-score"
+	})
+	minScoreOf := func(path string) int {
+		if minScoreSet {
+			return *minScore
+		}
+		// The profile was already loaded, and cached, by lintFiles
+		profile, _ := configs.forFile(path)
+		return profile.MinScore
+	}
+	code = checkThenHide(files, code, failOnLevels[*failOn], failOnLevels[*minSeverity], minScoreOf)
+
+	if writeReport != nil {
+		if err := writeReport(os.Stdout, files); err != nil {
+			log.Println("Error writing report:", err)
+			os.Exit(exitUsage)
+		}
+	} else {
+		opts := report.TextOptions{Color: useColor(os.Stdout)}
+		if err := report.WriteText(os.Stdout, files, opts); err != nil {
+			log.Println("Error writing report:", err)
+			os.Exit(exitUsage)
+		}
+	}
+
+	os.Exit(code)
+}
+
+// lintFiles lints every Dockerfile with its configuration for the build
+// described by build, reading the "-" path from stdin and reporting it as
+// stdinFilename. Files that can't be
+// read, configured or parsed are logged and left out of the results; the
+// returned exit code reflects the worst failure, read and configuration
+// errors taking precedence over parse errors.
+func lintFiles(paths []string, stdin io.Reader, stdinFilename string, configs *configLoader, build parse.LintInput) ([]report.File, int) {
+	files := make([]report.File, 0, len(paths))
+	code := exitOK
+
+	for _, path := range paths {
+		var content []byte
+		var err error
+		if path == stdinPath {
+			path = stdinFilename
+			content, err = io.ReadAll(stdin)
 		} else {
-			log.Printf("Line %d-%d: [%s] %s\n", rule.StartLine, rule.EndLine, rule.Code, rule.Description)
+			content, err = os.ReadFile(path)
+		}
+		if err != nil {
+			log.Printf("Error reading %s: %v", path, err)
+			code = exitUsage
+			continue
+		}
+
+		// The configuration of stdin is looked up from the directory of its
+		// filename, so --stdin-filename also selects the project config
+		profile, err := configs.forFile(path)
+		if err != nil {
+			log.Printf("Error loading configuration for %s: %v", path, err)
+			code = exitUsage
+			continue
+		}
+
+		input := build
+		input.Filename = path
+		input.Content = string(content)
+		result, err := parse.Lint(context.Background(), input, parse.Options{Config: profile.Config})
+		if err != nil {
+			log.Printf("Error parsing %s: %v", path, err)
+			if code == exitOK {
+				code = exitParse
+			}
+			continue
+		}
+
+		files = append(files, report.File{Path: path, Result: result.Result, Content: content})
+	}
+
+	return files, code
+}
+
+// hideSuppressed removes the suppressed rules from the results so that
+// reporters leave them out
+func hideSuppressed(files []report.File) {
+	for _, file := range files {
+		file.Result.Suppressed = nil
+	}
+}
+
+// hideBelow removes the rules whose severity ranks below minRank from the
+// results. The scores are left as they are, so hidden warnings still count.
+func hideBelow(files []report.File, minRank int) {
+	keep := func(rules []parse.Rule) []parse.Rule {
+		kept := rules[:0]
+		for _, rule := range rules {
+			if failOnLevels[string(rule.Severity)] >= minRank {
+				kept = append(kept, rule)
+			}
 		}
+		return kept
+	}
+
+	for _, file := range files {
+		file.Result.Rules = keep(file.Result.Rules)
+		if file.Result.Suppressed != nil {
+			file.Result.Suppressed = keep(file.Result.Suppressed)
+		}
+	}
+}
+
+// checkThenHide checks the thresholds when code, the exit code of linting,
+// is exitOK, and only then hides the rules ranking below minRank, so that
+// --min-severity narrows the report without hiding a finding that fails the
+// run. It returns the exit code of the run.
+func checkThenHide(files []report.File, code, failOn, minRank int, minScoreOf func(path string) int) int {
+	if code == exitOK {
+		code = checkThresholds(files, failOn, minScoreOf)
+	}
+	hideBelow(files, minRank)
+	return code
+}
+
+// checkThresholds returns exitFindings when a rule reaches the failOn rank
+// or a file scores below its minimum score, and logs why
+func checkThresholds(files []report.File, failOn int, minScoreOf func(path string) int) int {
+	code := exitOK
+	for _, file := range files {
+		if failOn > 0 {
+			for _, rule := range file.Result.Rules {
+				if failOnLevels[string(rule.Severity)] >= failOn {
+					log.Printf("%s: found %s rule %s", file.Path, rule.Severity, rule.Code)
+					code = exitFindings
+					break
+				}
+			}
+		}
+		if minScore := minScoreOf(file.Path); file.Result.Score < minScore {
+			log.Printf("%s: score %d is below the minimum of %d", file.Path, file.Result.Score, minScore)
+			code = exitFindings
+		}
+	}
+	return code
+}
+
+// printPreset writes the rule codes a preset turns on with their severity,
+// one per line in alphabetical order, and returns the exit code
+func printPreset(w io.Writer, name string) int {
+	presetConfig, err := parse.Preset(name)
+	if err != nil {
+		log.Println(err)
+		return exitUsage
+	}
+
+	var codes []string
+	width := 0
+	for code, ruleConfig := range presetConfig.Rules {
+		if *ruleConfig.Enabled {
+			codes = append(codes, code)
+			width = max(width, len(code))
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		fmt.Fprintf(w, "%-*s  %s\n", width, code, presetConfig.Rules[code].Severity)
+	}
+	return exitOK
+}
+
+// useColor reports whether the text report should be colored: only when f
+// is a terminal and the NO_COLOR convention (https://no-color.org) isn't set
+func useColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
 	}
-	log.Println("------")
-	log.Printf("Dockerfile Score: %d/100\n", result.Score)
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/deckrun/dockadvisor/report"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintFiles(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, os.WriteFile(valid, []byte("FROM alpine:3.20\nWORKDIR app\n"), 0o644))
+	empty := filepath.Join(dir, "Dockerfile.empty")
+	require.NoError(t, os.WriteFile(empty, []byte(""), 0o644))
+	missing := filepath.Join(dir, "Dockerfile.missing")
+
+	tests := []struct {
+		name          string
+		paths         []string
+		stdin         string
+		expectedCode  int
+		expectedPaths []string
+	}{
+		{
+			name:          "valid file",
+			paths:         []string{valid},
+			expectedCode:  exitOK,
+			expectedPaths: []string{valid},
+		},
+		{
+			name:          "stdin is reported under the stdin filename",
+			paths:         []string{stdinPath, valid},
+			stdin:         "FROM alpine:3.20\n",
+			expectedCode:  exitOK,
+			expectedPaths: []string{"app/Dockerfile", valid},
+		},
+		{
+			name:          "empty stdin",
+			paths:         []string{stdinPath},
+			expectedCode:  exitParse,
+			expectedPaths: []string{},
+		},
+		{
+			name:          "parse failure",
+			paths:         []string{valid, empty},
+			expectedCode:  exitParse,
+			expectedPaths: []string{valid},
+		},
+		{
+			name:          "read failure takes precedence",
+			paths:         []string{empty, missing, valid},
+			expectedCode:  exitUsage,
+			expectedPaths: []string{valid},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, code := lintFiles(tt.paths, strings.NewReader(tt.stdin), "app/Dockerfile", newConfigLoader(""), parse.LintInput{})
+			require.Equal(t, tt.expectedCode, code)
+
+			paths := []string{}
+			for _, file := range files {
+				paths = append(paths, file.Path)
+			}
+			require.Equal(t, tt.expectedPaths, paths)
+		})
+	}
+}
+
+func TestLintFilesConfig(t *testing.T) {
+	dir := t.TempDir()
+	dockerfile := []byte("FROM alpine:3.20\nWORKDIR app\n")
+
+	configured := filepath.Join(dir, "service", "Dockerfile")
+	require.NoError(t, os.MkdirAll(filepath.Dir(configured), 0o755))
+	require.NoError(t, os.WriteFile(configured, dockerfile, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".dockadvisor.yaml"),
+		[]byte("rules:\n  WorkdirRelativePath:\n    severity: error\n"), 0o644))
+
+	t.Run("nearest configuration applies", func(t *testing.T) {
+		files, code := lintFiles([]string{configured}, strings.NewReader(""), "", newConfigLoader(""), parse.LintInput{})
+		require.Equal(t, exitOK, code)
+		require.Len(t, files, 1)
+		require.Equal(t, parse.SeverityError, files[0].Result.Rules[0].Severity)
+	})
+
+	t.Run("stdin uses the directory of its filename", func(t *testing.T) {
+		stdinFilename := filepath.Join(dir, "other", "Dockerfile")
+		files, code := lintFiles([]string{stdinPath}, strings.NewReader(string(dockerfile)), stdinFilename, newConfigLoader(""), parse.LintInput{})
+		require.Equal(t, exitOK, code)
+		require.Equal(t, parse.SeverityError, files[0].Result.Rules[0].Severity)
+	})
+
+	t.Run("explicit configuration", func(t *testing.T) {
+		explicit := filepath.Join(t.TempDir(), "lint.yaml")
+		require.NoError(t, os.WriteFile(explicit, []byte("rules:\n  WorkdirRelativePath:\n    enabled: false\n"), 0o644))
+
+		files, code := lintFiles([]string{configured}, strings.NewReader(""), "", newConfigLoader(explicit), parse.LintInput{})
+		require.Equal(t, exitOK, code)
+		require.Empty(t, files[0].Result.Rules)
+	})
+
+	t.Run("override matching the path relative to the configuration", func(t *testing.T) {
+		explicit := filepath.Join(dir, "lint.yaml")
+		require.NoError(t, os.WriteFile(explicit, []byte("min-score: 100\noverrides:\n  - files: [service/Dockerfile]\n    min-score: 90\n"), 0o644))
+		configs := newConfigLoader(explicit)
+
+		files, code := lintFiles([]string{configured}, strings.NewReader(""), "", configs, parse.LintInput{})
+		require.Equal(t, exitOK, code)
+		profile, err := configs.forFile(configured)
+		require.NoError(t, err)
+		require.Equal(t, 90, profile.MinScore)
+		require.Equal(t, 95, files[0].Result.Score)
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		broken := filepath.Join(dir, "broken", "Dockerfile")
+		require.NoError(t, os.MkdirAll(filepath.Dir(broken), 0o755))
+		require.NoError(t, os.WriteFile(broken, dockerfile, 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "broken", ".dockadvisor.yaml"), []byte("rules: [\n"), 0o644))
+
+		files, code := lintFiles([]string{broken, configured}, strings.NewReader(""), "", newConfigLoader(""), parse.LintInput{})
+		require.Equal(t, exitUsage, code)
+		require.Len(t, files, 1)
+	})
+}
+
+func TestPrintPreset(t *testing.T) {
+	var stdout bytes.Buffer
+	require.Equal(t, exitOK, printPreset(&stdout, "security"))
+	require.Equal(t, `AddRemoteWithoutChecksum  warning
+FromUnpinnedImage         warning
+InvalidDefaultArgInFrom   error
+RunSecurityInsecure       warning
+SecretsUsedInArgOrEnv     warning
+UndefinedArgInFrom        error
+UserRoot                  warning
+`, stdout.String())
+
+	require.Equal(t, exitUsage, printPreset(&stdout, "lenient"))
+}
+
+func TestConfigLoaderPreset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".dockadvisor.yaml"),
+		[]byte("preset: minimal\nrules:\n  WorkdirRelativePath:\n    enabled: true\n"), 0o644))
+
+	configs := newConfigLoader("")
+	configs.preset = "strict"
+	profile, err := configs.forFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "strict", profile.Config.Preset, "--preset replaces the configured preset")
+	require.Contains(t, profile.Config.Rules, "WorkdirRelativePath", "rule settings are kept")
+
+	configs = newConfigLoader("")
+	configs.preset = "strict"
+	profile, err = configs.forFile(filepath.Join(t.TempDir(), "Dockerfile"))
+	require.NoError(t, err)
+	require.Equal(t, "strict", profile.Config.Preset, "applies without a configuration file")
+}
+
+func TestHideBelow(t *testing.T) {
+	result := &parse.Result{
+		Score: 95,
+		Rules: []parse.Rule{
+			{Code: "FromPinDigest", Severity: parse.SeverityHint},
+			{Code: "WorkdirRelativePath", Severity: parse.SeverityWarning},
+			{Code: "RunCacheMount", Severity: parse.SeverityInfo},
+		},
+	}
+	files := []report.File{{Path: "Dockerfile", Result: result}}
+
+	hideBelow(files, failOnLevels["info"])
+	require.Equal(t, []parse.Rule{
+		{Code: "WorkdirRelativePath", Severity: parse.SeverityWarning},
+		{Code: "RunCacheMount", Severity: parse.SeverityInfo},
+	}, result.Rules)
+
+	hideBelow(files, failOnLevels["error"])
+	require.Empty(t, result.Rules)
+	require.Equal(t, 95, result.Score, "hiding rules doesn't change the score")
+}
+
+func TestCheckThresholds(t *testing.T) {
+	file := func(score int, severities ...parse.Severity) report.File {
+		result := &parse.Result{Score: score}
+		for _, severity := range severities {
+			result.Rules = append(result.Rules, parse.Rule{Code: "SomeRule", Severity: severity})
+		}
+		return report.File{Path: "Dockerfile", Result: result}
+	}
+
+	tests := []struct {
+		name         string
+		files        []report.File
+		failOn       string
+		minScore     int
+		expectedCode int
+	}{
+		{
+			name:         "clean file passes",
+			files:        []report.File{file(100)},
+			failOn:       "warning",
+			minScore:     100,
+			expectedCode: exitOK,
+		},
+		{
+			name:         "fatal fails by default",
+			files:        []report.File{file(0, parse.SeverityFatal)},
+			failOn:       "fatal",
+			expectedCode: exitFindings,
+		},
+		{
+			name:         "errors pass with fail-on fatal",
+			files:        []report.File{file(85, parse.SeverityError)},
+			failOn:       "fatal",
+			expectedCode: exitOK,
+		},
+		{
+			name:         "errors fail with fail-on error",
+			files:        []report.File{file(100), file(85, parse.SeverityError)},
+			failOn:       "error",
+			expectedCode: exitFindings,
+		},
+		{
+			name:         "warnings pass with fail-on error",
+			files:        []report.File{file(95, parse.SeverityWarning)},
+			failOn:       "error",
+			expectedCode: exitOK,
+		},
+		{
+			name:         "warnings fail with fail-on warning",
+			files:        []report.File{file(95, parse.SeverityWarning)},
+			failOn:       "warning",
+			expectedCode: exitFindings,
+		},
+		{
+			name:         "fail-on none ignores fatal rules",
+			files:        []report.File{file(0, parse.SeverityFatal)},
+			failOn:       "none",
+			expectedCode: exitOK,
+		},
+		{
+			name:         "score below minimum",
+			files:        []report.File{file(100), file(70, parse.SeverityError, parse.SeverityError)},
+			failOn:       "none",
+			minScore:     80,
+			expectedCode: exitFindings,
+		},
+		{
+			name:         "score at minimum",
+			files:        []report.File{file(80, parse.SeverityWarning, parse.SeverityWarning, parse.SeverityWarning, parse.SeverityWarning)},
+			failOn:       "none",
+			minScore:     80,
+			expectedCode: exitOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := checkThresholds(tt.files, failOnLevels[tt.failOn], func(string) int { return tt.minScore })
+			require.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
+
+func TestBuildInput(t *testing.T) {
+	t.Setenv("VERSION", "1.2.3")
+	input := buildInput([]string{"BASE=golang", "VERSION", "UNSET_BUILD_ARG", "EMPTY="}, "release", "linux/arm64")
+	require.Equal(t, parse.LintInput{
+		BuildArgs: map[string]string{"BASE": "golang", "VERSION": "1.2.3", "EMPTY": ""},
+		Target:    "release",
+		Platform:  "linux/arm64",
+	}, input)
+
+	require.Nil(t, buildInput(nil, "", "").BuildArgs)
+}
+
+func TestCheckThenHide(t *testing.T) {
+	result := &parse.Result{
+		Score: 95,
+		Rules: []parse.Rule{{Code: "WorkdirRelativePath", Severity: parse.SeverityWarning}},
+	}
+	files := []report.File{{Path: "Dockerfile", Result: result}}
+	minScoreOf := func(string) int { return 0 }
+
+	// --min-severity error --fail-on warning
+	code := checkThenHide(files, exitOK, failOnLevels["warning"], failOnLevels["error"], minScoreOf)
+	require.Equal(t, exitFindings, code, "a hidden warning still fails the run")
+	require.Empty(t, result.Rules, "the warning is left out of the report")
+
+	result.Rules = []parse.Rule{{Code: "WorkdirRelativePath", Severity: parse.SeverityWarning}}
+	code = checkThenHide(files, exitParse, failOnLevels["warning"], failOnLevels["error"], minScoreOf)
+	require.Equal(t, exitParse, code, "lint failures take precedence")
+}
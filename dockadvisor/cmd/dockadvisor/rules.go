@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/deckrun/dockadvisor/catalog"
+)
+
+// runRules runs the "rules" subcommand, which lists every rule code with
+// its default severity, category and summary, and returns the exit code
+func runRules(args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("rules", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		log.Println("Usage: dockadvisor rules [--format text|json]")
+		return exitUsage
+	}
+
+	rules := catalog.Rules()
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(rules); err != nil {
+			log.Println("Error writing rules:", err)
+			return exitUsage
+		}
+	case "text":
+		codeWidth, severityWidth, categoryWidth := 0, 0, 0
+		for _, rule := range rules {
+			codeWidth = max(codeWidth, len(rule.Code))
+			severityWidth = max(severityWidth, len(rule.Severity))
+			categoryWidth = max(categoryWidth, len(rule.Category))
+		}
+		for _, rule := range rules {
+			line := fmt.Sprintf("%-*s  %-*s  %-*s  %s", codeWidth, rule.Code, severityWidth, rule.Severity, categoryWidth, rule.Category, rule.Summary)
+			fmt.Fprintln(stdout, strings.TrimRight(line, " "))
+		}
+	default:
+		log.Printf("Unknown output format %q, expected one of: text, json", *format)
+		return exitUsage
+	}
+	return exitOK
+}
+
+// runExplain runs the "explain" subcommand, which prints the documentation
+// of a rule code, and returns the exit code
+func runExplain(args []string, stdout io.Writer) int {
+	if len(args) != 1 {
+		log.Println("Usage: dockadvisor explain <Code>")
+		return exitUsage
+	}
+
+	rule, ok := catalog.Lookup(args[0])
+	if !ok {
+		log.Printf("Unknown rule %q, run \"dockadvisor rules\" to list them", args[0])
+		return exitUsage
+	}
+
+	details := []string{string(rule.Severity)}
+	if rule.Category != "" {
+		details = append(details, rule.Category)
+	}
+	if rule.BuildKit {
+		details = append(details, "BuildKit check")
+	}
+	if rule.OptIn {
+		details = append(details, "opt-in")
+	}
+	fmt.Fprintf(stdout, "%s (%s)\n", rule.Code, strings.Join(details, ", "))
+	if rule.Summary != "" {
+		fmt.Fprintf(stdout, "%s\n", rule.Summary)
+	}
+	if len(rule.Options) != 0 {
+		fmt.Fprintf(stdout, "Options: %s\n", strings.Join(rule.Options, ", "))
+	}
+	if rule.Doc == "" {
+		fmt.Fprintln(stdout, "\nNo documentation is available for this rule.")
+		return exitOK
+	}
+	fmt.Fprintf(stdout, "\n%s", rule.Doc)
+	return exitOK
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/catalog"
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRules(t *testing.T) {
+	var stdout bytes.Buffer
+	require.Equal(t, exitOK, runRules(nil, &stdout))
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	require.Len(t, lines, len(parse.Rules()))
+	require.Contains(t, lines, "WorkdirRelativePath              warning  WORKDIR      WORKDIR should be an absolute path")
+
+	stdout.Reset()
+	require.Equal(t, exitOK, runRules([]string{"--format", "json"}, &stdout))
+	var rules []catalog.Rule
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &rules))
+	require.Equal(t, catalog.Rules(), rules)
+
+	require.Equal(t, exitUsage, runRules([]string{"--format", "yaml"}, &stdout))
+	require.Equal(t, exitUsage, runRules([]string{"WorkdirRelativePath"}, &stdout))
+}
+
+func TestRunExplain(t *testing.T) {
+	var stdout bytes.Buffer
+	require.Equal(t, exitOK, runExplain([]string{"RunInvalidNetworkFlag"}, &stdout))
+	require.True(t, strings.HasPrefix(stdout.String(), `RunInvalidNetworkFlag (error, RUN)
+The --network flag of RUN must be default, none or host
+Options: networks
+
+## Output
+`), stdout.String())
+
+	stdout.Reset()
+	require.Equal(t, exitOK, runExplain([]string{"workdirrelativepath"}, &stdout))
+	require.True(t, strings.HasPrefix(stdout.String(), "WorkdirRelativePath (warning, WORKDIR, BuildKit check)\n"), stdout.String())
+
+	require.Equal(t, exitUsage, runExplain([]string{"LatestTag"}, &stdout))
+	require.Equal(t, exitUsage, runExplain(nil, &stdout))
+}
@@ -0,0 +1,313 @@
+// Package config loads dockadvisor project configuration files.
+//
+// A configuration file is named .dockadvisor.yaml (or .dockadvisor.yml) and
+// applies to the Dockerfiles in its directory and below:
+//
+//	preset: recommended
+//	rules:
+//	  MaintainerDeprecated:
+//	    enabled: false
+//	  JSONArgsRecommended:
+//	    severity: error
+//	  SecretsUsedInArgOrEnv:
+//	    options:
+//	      tokens: [dsn]
+//	  RunInvalidNetworkFlag:
+//	    options:
+//	      networks: [bridge]
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/deckrun/dockadvisor/internal/pathmatch"
+	"github.com/deckrun/dockadvisor/parse"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file
+const FileName = ".dockadvisor.yaml"
+
+// fileNames lists the accepted configuration file names in order of preference
+var fileNames = []string{FileName, ".dockadvisor.yml"}
+
+// Find looks for a configuration file in dir and its parent directories and
+// returns its path, or "" when there is none
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range fileNames {
+			path := filepath.Join(dir, name)
+			info, err := os.Stat(path)
+			if err == nil && !info.IsDir() {
+				return path, nil
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// File is a configuration file. Its top-level settings apply to every
+// Dockerfile and its overrides adjust them for the Dockerfiles they match.
+type File struct {
+	parse.Config `yaml:",inline"`
+
+	// MinScore is the lowest acceptable score, 0 means no minimum
+	MinScore *int `yaml:"min-score"`
+
+	Overrides []Override `yaml:"overrides"`
+
+	// root is the directory override patterns are relative to; paths are
+	// used as given when it is empty
+	root string
+}
+
+// Override adjusts the settings of the Dockerfiles matching one of its
+// patterns. A pattern without a "/" matches the file name in any directory.
+type Override struct {
+	Files    []string                    `yaml:"files"`
+	Rules    map[string]parse.RuleConfig `yaml:"rules"`
+	MinScore *int                        `yaml:"min-score"`
+}
+
+// Profile is the configuration that applies to a single Dockerfile
+type Profile struct {
+	Config   *parse.Config
+	MinScore int // 0 when no minimum is configured
+}
+
+// Load reads and validates the configuration file at path
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if file.root, err = RepositoryRoot(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Parse decodes and validates a YAML configuration. Unknown keys are
+// rejected so that typos don't silently leave rules enabled.
+// Override patterns of a parsed configuration are matched against paths as
+// given.
+func Parse(data []byte) (*File, error) {
+	file := &File{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Validate checks the settings, minimum scores and override patterns
+func (f *File) Validate() error {
+	if err := f.Config.Validate(); err != nil {
+		return err
+	}
+	if err := validateMinScore(f.MinScore); err != nil {
+		return err
+	}
+
+	for i, override := range f.Overrides {
+		if len(override.Files) == 0 {
+			return fmt.Errorf("overrides[%d]: files must list at least one pattern", i)
+		}
+		for _, pattern := range override.Files {
+			if err := pathmatch.Validate(pattern); err != nil {
+				return fmt.Errorf("overrides[%d]: invalid pattern %q: %w", i, pattern, err)
+			}
+		}
+		if err := (&parse.Config{Rules: override.Rules}).Validate(); err != nil {
+			return fmt.Errorf("overrides[%d]: %w", i, err)
+		}
+		if err := validateMinScore(override.MinScore); err != nil {
+			return fmt.Errorf("overrides[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateMinScore(minScore *int) error {
+	if minScore != nil && (*minScore < 0 || *minScore > 100) {
+		return fmt.Errorf("min-score must be between 0 and 100, got %d", *minScore)
+	}
+	return nil
+}
+
+// Profile returns the configuration of the Dockerfile at path: the top-level
+// settings with every matching override applied on top. When several
+// overrides match, the most specific one wins, and among equally specific
+// ones the last in the file. Profile returns an empty profile for a nil file.
+func (f *File) Profile(path string) Profile {
+	if f == nil {
+		return Profile{}
+	}
+
+	config := f.Config
+	minScore := f.MinScore
+
+	matches := f.matchingOverrides(f.relative(path))
+	if len(matches) != 0 {
+		config.Rules = make(map[string]parse.RuleConfig, len(f.Rules))
+		for code, ruleConfig := range f.Rules {
+			config.Rules[code] = ruleConfig
+		}
+	}
+	for _, override := range matches {
+		for code, ruleConfig := range override.Rules {
+			config.Rules[code] = config.Rules[code].Merge(ruleConfig)
+		}
+		if override.MinScore != nil {
+			minScore = override.MinScore
+		}
+	}
+
+	profile := Profile{Config: &config}
+	if minScore != nil {
+		profile.MinScore = *minScore
+	}
+	return profile
+}
+
+// matchingOverrides returns the overrides matching the slash-separated
+// name, the least specific first so that later ones take precedence
+func (f *File) matchingOverrides(name string) []Override {
+	type match struct {
+		override    Override
+		specificity specificity
+	}
+
+	var matches []match
+	for _, override := range f.Overrides {
+		best, ok := specificity{}, false
+		for _, pattern := range override.Files {
+			if matchPattern(pattern, name) {
+				if s := patternSpecificity(pattern); !ok || best.less(s) {
+					best, ok = s, true
+				}
+			}
+		}
+		if ok {
+			matches = append(matches, match{override, best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].specificity.less(matches[j].specificity)
+	})
+
+	overrides := make([]Override, len(matches))
+	for i, m := range matches {
+		overrides[i] = m.override
+	}
+	return overrides
+}
+
+// matchPattern reports whether the pattern matches the slash-separated
+// name, or its last element when the pattern has no "/"
+func matchPattern(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		return pathmatch.Match(pattern, name[strings.LastIndex(name, "/")+1:])
+	}
+	return pathmatch.Match(strings.TrimPrefix(pattern, "/"), name)
+}
+
+// specificity ranks patterns: the one with more literal path segments is
+// more specific, then the one with more literal characters
+type specificity struct {
+	segments   int
+	characters int
+}
+
+func (s specificity) less(other specificity) bool {
+	if s.segments != other.segments {
+		return s.segments < other.segments
+	}
+	return s.characters < other.characters
+}
+
+func patternSpecificity(pattern string) specificity {
+	var s specificity
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment != "" && !pathmatch.HasMeta(segment) {
+			s.segments++
+		}
+		for _, r := range segment {
+			if !strings.ContainsRune("*?[]", r) {
+				s.characters++
+			}
+		}
+	}
+	return s
+}
+
+// relative returns path relative to the repository root with forward
+// slashes. Paths outside of the root are returned absolute, so that only
+// file name patterns match them.
+func (f *File) relative(path string) string {
+	if f.root != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+			if rel, err := filepath.Rel(f.root, abs); err == nil {
+				if rel := filepath.ToSlash(rel); rel != ".." && !strings.HasPrefix(rel, "../") {
+					path = rel
+				}
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// RepositoryRoot returns the absolute path of the nearest directory at or
+// above dir that contains .git, or of dir itself when there is none
+func RepositoryRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current, nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir, nil
+		}
+		current = parent
+	}
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "services", "api")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	path, err := Find(nested)
+	require.NoError(t, err)
+	require.Empty(t, path, "no configuration above the directory")
+
+	rootConfig := filepath.Join(root, FileName)
+	require.NoError(t, os.WriteFile(rootConfig, []byte("rules: {}\n"), 0o644))
+	path, err = Find(nested)
+	require.NoError(t, err)
+	require.Equal(t, rootConfig, path, "walks up to the nearest configuration")
+
+	nestedConfig := filepath.Join(root, "services", ".dockadvisor.yml")
+	require.NoError(t, os.WriteFile(nestedConfig, []byte("rules: {}\n"), 0o644))
+	path, err = Find(nested)
+	require.NoError(t, err)
+	require.Equal(t, nestedConfig, path, "the nearest configuration wins")
+}
+
+func TestParse(t *testing.T) {
+	weight := 40
+
+	tests := []struct {
+		name        string
+		data        string
+		expected    *parse.Config
+		expectedErr string
+	}{
+		{
+			name:     "empty file",
+			data:     "",
+			expected: &parse.Config{},
+		},
+		{
+			name: "rules",
+			data: `rules:
+  MaintainerDeprecated:
+    enabled: false
+  JSONArgsRecommended:
+    severity: error
+  SecretsUsedInArgOrEnv:
+    options:
+      tokens: [dsn]
+`,
+			expected: &parse.Config{Rules: map[string]parse.RuleConfig{
+				"MaintainerDeprecated":  {Enabled: new(bool)},
+				"JSONArgsRecommended":   {Severity: parse.SeverityError},
+				"SecretsUsedInArgOrEnv": {Options: map[string][]string{"tokens": {"dsn"}}},
+			}},
+		},
+		{
+			name: "scoring",
+			data: `scoring:
+  weights:
+    warning: 2
+    fatal: 40
+  cap: 30
+rules:
+  SecretsUsedInArgOrEnv:
+    weight: 40
+`,
+			expected: &parse.Config{
+				Scoring: &parse.Scoring{Weights: map[parse.Severity]int{parse.SeverityWarning: 2, parse.SeverityFatal: 40}, Cap: 30},
+				Rules:   map[string]parse.RuleConfig{"SecretsUsedInArgOrEnv": {Weight: &weight}},
+			},
+		},
+		{
+			name:        "unknown key",
+			data:        "rules:\n  MaintainerDeprecated:\n    enable: false\n",
+			expectedErr: "field enable not found",
+		},
+		{
+			name:        "invalid severity",
+			data:        "rules:\n  MaintainerDeprecated:\n    severity: critical\n",
+			expectedErr: `invalid severity "critical"`,
+		},
+		{
+			name:        "invalid min-score",
+			data:        "min-score: 120\n",
+			expectedErr: "min-score must be between 0 and 100, got 120",
+		},
+		{
+			name:        "override without files",
+			data:        "overrides:\n  - min-score: 50\n",
+			expectedErr: "overrides[0]: files must list at least one pattern",
+		},
+		{
+			name:        "invalid override pattern",
+			data:        "overrides:\n  - files: [\"test/[\"]\n",
+			expectedErr: `overrides[0]: invalid pattern "test/["`,
+		},
+		{
+			name:        "invalid override rule",
+			data:        "overrides:\n  - files: [Dockerfile.dev]\n    rules:\n      MaintainerDeprecated:\n        severity: critical\n",
+			expectedErr: `overrides[0]: rule MaintainerDeprecated: invalid severity "critical"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := Parse([]byte(tt.data))
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, &file.Config)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(path, []byte("rules:\n  WorkdirRelativePath:\n    severity: bad\n"), 0o644))
+
+	_, err := Load(path)
+	require.ErrorContains(t, err, path+": rule WorkdirRelativePath: invalid severity")
+
+	_, err = Load(filepath.Join(t.TempDir(), FileName))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestProfile(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o755))
+
+	// The configuration lives below the repository root, so patterns must
+	// be resolved from the root rather than from the file's directory
+	path := filepath.Join(root, ".github", FileName)
+	require.NoError(t, os.WriteFile(path, []byte(`min-score: 80
+rules:
+  WorkdirRelativePath:
+    severity: error
+  MaintainerDeprecated:
+    enabled: false
+overrides:
+  - files: [Dockerfile.dev]
+    min-score: 60
+    rules:
+      WorkdirRelativePath:
+        enabled: false
+  - files: ["test/**/Dockerfile"]
+    min-score: 40
+    rules:
+      WorkdirRelativePath:
+        severity: warning
+  - files: [test/integration/Dockerfile]
+    min-score: 0
+`), 0o644))
+
+	file, err := Load(path)
+	require.NoError(t, err)
+
+	disabled := false
+	tests := []struct {
+		name     string
+		path     string
+		rules    map[string]parse.RuleConfig
+		minScore int
+	}{
+		{
+			name: "no override",
+			path: filepath.Join(root, "Dockerfile"),
+			rules: map[string]parse.RuleConfig{
+				"WorkdirRelativePath":  {Severity: parse.SeverityError},
+				"MaintainerDeprecated": {Enabled: &disabled},
+			},
+			minScore: 80,
+		},
+		{
+			name: "file name pattern matches in any directory",
+			path: filepath.Join(root, "services", "api", "Dockerfile.dev"),
+			rules: map[string]parse.RuleConfig{
+				"WorkdirRelativePath":  {Enabled: &disabled, Severity: parse.SeverityError},
+				"MaintainerDeprecated": {Enabled: &disabled},
+			},
+			minScore: 60,
+		},
+		{
+			name: "path pattern",
+			path: filepath.Join(root, "test", "unit", "Dockerfile"),
+			rules: map[string]parse.RuleConfig{
+				"WorkdirRelativePath":  {Severity: parse.SeverityWarning},
+				"MaintainerDeprecated": {Enabled: &disabled},
+			},
+			minScore: 40,
+		},
+		{
+			name: "most specific override wins",
+			path: filepath.Join(root, "test", "integration", "Dockerfile"),
+			rules: map[string]parse.RuleConfig{
+				"WorkdirRelativePath":  {Severity: parse.SeverityWarning},
+				"MaintainerDeprecated": {Enabled: &disabled},
+			},
+			minScore: 0,
+		},
+		{
+			name: "paths outside the repository only match file names",
+			path: filepath.Join(t.TempDir(), "test", "unit", "Dockerfile"),
+			rules: map[string]parse.RuleConfig{
+				"WorkdirRelativePath":  {Severity: parse.SeverityError},
+				"MaintainerDeprecated": {Enabled: &disabled},
+			},
+			minScore: 80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := file.Profile(tt.path)
+			require.Equal(t, tt.rules, profile.Config.Rules)
+			require.Equal(t, tt.minScore, profile.MinScore)
+		})
+	}
+
+	t.Run("overrides don't modify the top-level settings", func(t *testing.T) {
+		file.Profile(filepath.Join(root, "Dockerfile.dev"))
+		require.Equal(t, parse.RuleConfig{Severity: parse.SeverityError}, file.Rules["WorkdirRelativePath"])
+	})
+
+	t.Run("nil file", func(t *testing.T) {
+		require.Equal(t, Profile{}, (*File)(nil).Profile("Dockerfile"))
+	})
+}
@@ -0,0 +1,241 @@
+// Package diff narrows dockadvisor findings down to the lines a change
+// touched, so that a pull request only reports what its author wrote.
+//
+// A finding is kept when its StartLine-EndLine range overlaps a changed
+// line. Findings of rules marked FileScoped, which depend on the whole file
+// rather than on the line they are reported on, such as
+// ConsistentInstructionCasing or the stage-graph checks like UndefinedVar,
+// are kept whenever the file changed at all, since an edit elsewhere, e.g.
+// removing an ARG, can cause them. Findings without a line are treated the
+// same way.
+//
+// WriteUnified writes the changes to a file in the unified format Parse
+// reads, as printed by the --fix-dry-run flag of the CLI.
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+// Range is an inclusive range of 1-based line numbers
+type Range struct {
+	Start int
+	End   int
+}
+
+// Changes maps slash-separated file paths, relative to the root of the
+// repository like the paths of git diff, to the lines changed in the new
+// version of each file. A file that is present with no ranges changed only
+// by deleting lines.
+type Changes map[string][]Range
+
+// hunkRegexp matches a hunk header and captures the old count and the new
+// start and count
+var hunkRegexp = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse reads the added and modified lines from a unified diff, such as the
+// output of git diff. File paths are taken from the "+++" headers with the
+// "b/" prefix of git removed; deleted files are left out.
+func Parse(r io.Reader) (Changes, error) {
+	changes := Changes{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	file := ""
+	line, oldRemaining, newRemaining := 0, 0, 0
+	for n := 1; scanner.Scan(); n++ {
+		text := scanner.Text()
+
+		// Inside a hunk, every line belongs to it until both sides are done
+		if oldRemaining > 0 || newRemaining > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if file != "" {
+					changes.add(file, Range{line, line})
+				}
+				line++
+				newRemaining--
+			case strings.HasPrefix(text, "-"):
+				oldRemaining--
+			case strings.HasPrefix(text, `\`):
+				// "\ No newline at end of file"
+			default:
+				line++
+				oldRemaining--
+				newRemaining--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			file = headerPath(text[len("+++ "):])
+			if file != "" {
+				if _, ok := changes[file]; !ok {
+					changes[file] = nil
+				}
+			}
+		case strings.HasPrefix(text, "@@ "):
+			match := hunkRegexp.FindStringSubmatch(text)
+			if match == nil {
+				return nil, fmt.Errorf("line %d: invalid hunk header %q", n, text)
+			}
+			oldRemaining = hunkCount(match[1])
+			line, _ = strconv.Atoi(match[2])
+			newRemaining = hunkCount(match[3])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// hunkCount parses the line count of a hunk header, which is 1 when omitted
+func hunkCount(count string) int {
+	if count == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(count)
+	return n
+}
+
+// headerPath returns the path of a "+++" header, or "" for /dev/null
+func headerPath(header string) string {
+	// Some tools append a timestamp after a tab
+	header, _, _ = strings.Cut(header, "\t")
+	if header == "/dev/null" {
+		return ""
+	}
+	return normalize(strings.TrimPrefix(header, "b/"))
+}
+
+// AddSpec adds the lines of a "path:start-end" or "path:line" spec. The
+// path is relative to the working directory, or absolute, and is resolved
+// against root, the root of the repository.
+func (c Changes) AddSpec(root, spec string) error {
+	i := strings.LastIndex(spec, ":")
+	if i <= 0 {
+		return fmt.Errorf("invalid changed lines %q, expected path:start-end", spec)
+	}
+	path, lines := spec[:i], spec[i+1:]
+
+	startText, endText, isRange := strings.Cut(lines, "-")
+	if !isRange {
+		endText = startText
+	}
+	start, err := strconv.Atoi(startText)
+	if err != nil || start < 1 {
+		return fmt.Errorf("invalid changed lines %q, expected path:start-end", spec)
+	}
+	end, err := strconv.Atoi(endText)
+	if err != nil || end < start {
+		return fmt.Errorf("invalid changed lines %q, expected path:start-end", spec)
+	}
+
+	c.add(relative(root, path), Range{start, end})
+	return nil
+}
+
+// add records a changed range, merging it with the ranges it touches
+func (c Changes) add(file string, r Range) {
+	ranges := append(c[file], r)
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+	merged := ranges[:1]
+	for _, next := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if next.Start <= last.End+1 {
+			last.End = max(last.End, next.End)
+			continue
+		}
+		merged = append(merged, next)
+	}
+	c[file] = merged
+}
+
+// Filter removes the findings of the Dockerfile at path, resolved against
+// root like the path of AddSpec, that are outside of the changed lines and
+// returns the number of findings removed. Suppressed
+// findings are filtered the same way. The score is left alone: it stays the
+// score of the whole Dockerfile, which a diff doesn't change.
+func (c Changes) Filter(root, path string, result *parse.Result) int {
+	ranges, touched := c[relative(root, path)]
+
+	fileScoped := map[string]bool{}
+	for _, rule := range parse.Rules() {
+		fileScoped[rule.Code] = rule.FileScoped
+	}
+
+	keep := func(rules []parse.Rule) []parse.Rule {
+		kept := rules[:0]
+		for _, rule := range rules {
+			if keepRule(rule, ranges, touched, fileScoped[rule.Code]) {
+				kept = append(kept, rule)
+			}
+		}
+		return kept
+	}
+
+	before := len(result.Rules)
+	result.Rules = keep(result.Rules)
+	if result.Suppressed != nil {
+		result.Suppressed = keep(result.Suppressed)
+	}
+	return before - len(result.Rules)
+}
+
+// keepRule reports whether a finding is on a changed line, or is file
+// scoped and the file changed
+func keepRule(rule parse.Rule, ranges []Range, touched, fileScoped bool) bool {
+	if rule.StartLine < 1 || fileScoped {
+		return touched
+	}
+
+	end := max(rule.EndLine, rule.StartLine)
+	for _, r := range ranges {
+		if r.Start <= end && rule.StartLine <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// relative returns path, relative to the working directory or absolute, as
+// a path relative to root in the form of the paths of Changes. Without a
+// root, or for a path outside of it, the path is only normalized.
+func relative(root, path string) string {
+	if root != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil && rel != ".." && !strings.HasPrefix(filepath.ToSlash(rel), "../") {
+				path = rel
+			}
+		}
+	}
+	return normalize(path)
+}
+
+// normalize returns path with forward slashes and without a leading "./",
+// the form paths in a diff are compared in. Absolute paths are made
+// relative to the working directory when possible.
+func normalize(path string) string {
+	if filepath.IsAbs(path) {
+		if abs, err := filepath.Abs("."); err == nil {
+			if rel, err := filepath.Rel(abs, path); err == nil && rel != ".." && !strings.HasPrefix(filepath.ToSlash(rel), "../") {
+				path = rel
+			}
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+}
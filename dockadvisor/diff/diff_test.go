@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	patch := `diff --git a/Dockerfile b/Dockerfile
+index 1111111..2222222 100644
+--- a/Dockerfile
++++ b/Dockerfile
+@@ -1,4 +1,5 @@
+ FROM alpine:3.20
+-WORKDIR /app
++WORKDIR app
++RUN make
+ COPY . .
+ CMD ["app"]
+@@ -10 +11,2 @@ EXPOSE 80
+-USER root
++USER app
++HEALTHCHECK CMD true
+diff --git a/services/api/Dockerfile b/services/api/Dockerfile
+--- a/services/api/Dockerfile
++++ b/services/api/Dockerfile
+@@ -3,2 +3 @@ FROM alpine
+ RUN make
+-RUN make install
+diff --git a/old/Dockerfile b/old/Dockerfile
+deleted file mode 100644
+--- a/old/Dockerfile
++++ /dev/null
+@@ -1 +0,0 @@
+-FROM alpine
+diff --git a/new.Dockerfile b/new.Dockerfile
+new file mode 100644
+--- /dev/null
++++ b/new.Dockerfile
+@@ -0,0 +1,2 @@
++FROM alpine
++++i
+\ No newline at end of file
+`
+
+	changes, err := Parse(strings.NewReader(patch))
+	require.NoError(t, err)
+	require.Equal(t, Changes{
+		"Dockerfile":              {{2, 3}, {11, 12}},
+		"services/api/Dockerfile": nil,
+		"new.Dockerfile":          {{1, 2}},
+	}, changes)
+
+	_, err = Parse(strings.NewReader("+++ b/Dockerfile\n@@ broken @@\n"))
+	require.ErrorContains(t, err, `line 2: invalid hunk header "@@ broken @@"`)
+}
+
+func TestAddSpec(t *testing.T) {
+	changes := Changes{}
+	require.NoError(t, changes.AddSpec("", "./Dockerfile:3-5"))
+	require.NoError(t, changes.AddSpec("", "Dockerfile:6"))
+	require.NoError(t, changes.AddSpec("", "Dockerfile:10-12"))
+	require.Equal(t, Changes{"Dockerfile": {{3, 6}, {10, 12}}}, changes)
+
+	for _, spec := range []string{"Dockerfile", ":3", "Dockerfile:0", "Dockerfile:5-3", "Dockerfile:a-b"} {
+		require.ErrorContains(t, changes.AddSpec("", spec), "expected path:start-end", spec)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	rules := []parse.Rule{
+		{StartLine: 2, EndLine: 2, Code: "WorkdirRelativePath", Severity: parse.SeverityWarning},
+		{StartLine: 4, EndLine: 6, Code: "RunMissingCommand", Severity: parse.SeverityError},
+		{StartLine: 8, EndLine: 8, Code: "UndefinedVar", Severity: parse.SeverityError},
+		{Code: "ParserWarning", Severity: parse.SeverityWarning},
+	}
+
+	tests := []struct {
+		name          string
+		changes       Changes
+		path          string
+		expectedCodes []string
+	}{
+		{
+			name:          "findings overlapping changed lines",
+			changes:       Changes{"Dockerfile": {{5, 5}}},
+			path:          "./Dockerfile",
+			expectedCodes: []string{"RunMissingCommand", "UndefinedVar", "ParserWarning"},
+		},
+		{
+			name:          "file changed only by deletions",
+			changes:       Changes{"Dockerfile": nil},
+			path:          "Dockerfile",
+			expectedCodes: []string{"UndefinedVar", "ParserWarning"},
+		},
+		{
+			name:          "unchanged file",
+			changes:       Changes{"other/Dockerfile": {{1, 10}}},
+			path:          "Dockerfile",
+			expectedCodes: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &parse.Result{Rules: append([]parse.Rule(nil), rules...), Score: 30}
+			removed := tt.changes.Filter("", tt.path, result)
+
+			var codes []string
+			for _, rule := range result.Rules {
+				codes = append(codes, rule.Code)
+			}
+			require.Equal(t, tt.expectedCodes, codes)
+			require.Equal(t, len(rules)-len(codes), removed)
+			require.Equal(t, 30, result.Score, "the score is the one of the whole file")
+		})
+	}
+}
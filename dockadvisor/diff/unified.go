@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// op is a line of a line-by-line comparison: kept (' '), removed ('-') or
+// added ('+')
+type op struct {
+	kind byte
+	line string
+}
+
+// WriteUnified writes the changes from old to new of the file at path as a
+// unified diff with git-style "a/" and "b/" headers, which Parse reads
+// back. Absolute paths outside of the working directory are written without
+// a prefix. Nothing is written when the contents are equal.
+func WriteUnified(w io.Writer, path, old, new string) error {
+	if old == new {
+		return nil
+	}
+
+	ops := compareLines(splitLines(old), splitLines(new))
+	path = normalize(path)
+	oldPath, newPath := "a/"+path, "b/"+path
+	if filepath.IsAbs(path) {
+		oldPath, newPath = path, path
+	}
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldPath, newPath); err != nil {
+		return err
+	}
+
+	// oldLine and newLine are the 1-based line numbers ops[i] starts at
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// A hunk starts contextLines before the change and extends until
+		// more than twice contextLines unchanged lines follow a change
+		start := max(0, i-contextLines)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*contextLines {
+				break
+			}
+		}
+		end = min(len(ops), end+contextLines)
+
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		oldCount, newCount := 0, 0
+		for _, o := range ops[start:end] {
+			if o.kind != '+' {
+				oldCount++
+			}
+			if o.kind != '-' {
+				newCount++
+			}
+		}
+		if _, err := fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount)); err != nil {
+			return err
+		}
+		for _, o := range ops[start:end] {
+			if err := writeLine(w, o); err != nil {
+				return err
+			}
+		}
+
+		for _, o := range ops[i:end] {
+			if o.kind != '+' {
+				oldLine++
+			}
+			if o.kind != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return nil
+}
+
+// hunkRange formats the start and count of one side of a hunk header. An
+// empty side starts at the line before it, as in diff -u.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// writeLine writes a line of a hunk, marking a last line without a line
+// break the way diff -u does
+func writeLine(w io.Writer, o op) error {
+	if line, ok := strings.CutSuffix(o.line, "\n"); ok {
+		_, err := fmt.Fprintf(w, "%c%s\n", o.kind, line)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%c%s\n\\ No newline at end of file\n", o.kind, o.line)
+	return err
+}
+
+// splitLines splits content into lines, each keeping its line break
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// compareLines returns the shortest edit from a to b through their longest
+// common subsequence of lines. Dockerfiles are short enough for its
+// quadratic cost.
+func compareLines(a, b []string) []op {
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || i < len(a) && common[i+1][j] >= common[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	return ops
+}
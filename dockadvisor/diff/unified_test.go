@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "equal contents",
+			old:      "FROM alpine\n",
+			new:      "FROM alpine\n",
+			expected: "",
+		},
+		{
+			name: "changes far apart are separate hunks",
+			old:  "FROM alpine\nrun a\n2\n3\n4\n5\n6\n7\n8\n9\nrun b\n",
+			new:  "FROM alpine\nRUN a\n2\n3\n4\n5\n6\n7\n8\n9\nRUN b\n",
+			expected: `--- a/Dockerfile
++++ b/Dockerfile
+@@ -1,5 +1,5 @@
+ FROM alpine
+-run a
++RUN a
+ 2
+ 3
+ 4
+@@ -8,4 +8,4 @@
+ 7
+ 8
+ 9
+-run b
++RUN b
+`,
+		},
+		{
+			name: "removed lines",
+			old:  "FROM alpine\nRUN a \\\n\n    b\n",
+			new:  "FROM alpine\nRUN a \\\n    b\n",
+			expected: `--- a/Dockerfile
++++ b/Dockerfile
+@@ -1,4 +1,3 @@
+ FROM alpine
+ RUN a \
+-
+     b
+`,
+		},
+		{
+			name: "no line break at the end",
+			old:  "FROM alpine\nEXPOSE 80/TCP",
+			new:  "FROM alpine\nEXPOSE 80/tcp",
+			expected: `--- a/Dockerfile
++++ b/Dockerfile
+@@ -1,2 +1,2 @@
+ FROM alpine
+-EXPOSE 80/TCP
+\ No newline at end of file
++EXPOSE 80/tcp
+\ No newline at end of file
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			require.NoError(t, WriteUnified(&out, "./Dockerfile", tt.old, tt.new))
+			require.Equal(t, tt.expected, out.String())
+
+			if tt.expected != "" {
+				changes, err := Parse(strings.NewReader(out.String()))
+				require.NoError(t, err)
+				require.Contains(t, changes, "Dockerfile", "the diff should be readable by Parse")
+			}
+		})
+	}
+}
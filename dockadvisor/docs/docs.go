@@ -0,0 +1,10 @@
+// Package docs embeds the documentation of the checks so that it is
+// available offline, see the catalog package.
+package docs
+
+import "embed"
+
+// Checks holds one Markdown file per rule code in its checks directory
+//
+//go:embed checks/*.md
+var Checks embed.FS
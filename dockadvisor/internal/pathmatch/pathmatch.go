@@ -0,0 +1,61 @@
+// Package pathmatch matches slash-separated paths against glob patterns
+// that support "**" in addition to the syntax of path.Match.
+package pathmatch
+
+import (
+	"path"
+	"strings"
+)
+
+// Match reports whether name matches the pattern. A "**" segment matches
+// zero or more path segments; every other segment is matched with
+// path.Match, so "*" never crosses a "/". Invalid patterns never match.
+func Match(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// Validate returns an error if the pattern is malformed
+func Validate(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasMeta reports whether s contains any glob metacharacters
+func HasMeta(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse repeated "**" and try every possible split
+			for len(pattern) > 1 && pattern[1] == "**" {
+				pattern = pattern[1:]
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
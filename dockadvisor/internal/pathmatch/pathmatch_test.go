@@ -0,0 +1,54 @@
+package pathmatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"Dockerfile", "Dockerfile", true},
+		{"Dockerfile", "app/Dockerfile", false},
+		{"*.Dockerfile", "web.Dockerfile", true},
+		{"*.Dockerfile", "app/web.Dockerfile", false},
+		{"app/*", "app/Dockerfile", true},
+		{"app/*", "app/sub/Dockerfile", false},
+		{"**/Dockerfile", "Dockerfile", true},
+		{"**/Dockerfile", "a/b/c/Dockerfile", true},
+		{"test/**/Dockerfile", "test/Dockerfile", true},
+		{"test/**/Dockerfile", "test/unit/api/Dockerfile", true},
+		{"test/**/Dockerfile", "src/test/Dockerfile", false},
+		{"vendor/**", "vendor", true},
+		{"vendor/**", "vendor/a/b", true},
+		{"**", "anything/at/all", true},
+		{"**/**/x", "x", true},
+		{"Dockerfile.?", "Dockerfile.a", true},
+		{"Dockerfile.[dp]*", "Dockerfile.dev", true},
+		{"Dockerfile.[dp]*", "Dockerfile.test", false},
+		{"[", "[", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Match(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate("test/**/Dockerfile"))
+	require.NoError(t, Validate("*.Dockerfile"))
+	require.Error(t, Validate("Dockerfile.[dev"))
+}
+
+func TestHasMeta(t *testing.T) {
+	require.True(t, HasMeta("*.Dockerfile"))
+	require.True(t, HasMeta("Dockerfile.?"))
+	require.True(t, HasMeta("Dockerfile.[ab]"))
+	require.False(t, HasMeta("docker/Dockerfile.dev"))
+}
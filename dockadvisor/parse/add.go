@@ -1,33 +1,53 @@
 package parse
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("ADD", CheckMetadata{
+		Description:  "Checks the flags and arguments of ADD instructions",
+		Instructions: []string{"ADD"},
+		Rules: []RuleMetadata{
+			{Code: "AddInvalidFlag", Severity: SeverityError},
+			{Code: "AddMissingArguments", Severity: SeverityError},
+			{Code: "AddRemoteWithoutChecksum", Severity: SeverityWarning, OptIn: true},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseADD(node)
+	}))
+}
+
 func parseADD(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "ADD requires at least source and destination arguments")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
+	// The arguments and every flag are checked independently so that every
+	// problem is reported at once
+	var addRules []Rule
+
 	// ADD requires at least 2 arguments: source and destination
 	// Count non-flag arguments
-	argCount := 0
+	var args []string
 	current := node.Next
 	for current != nil {
 		// Skip flags (--keep-git-dir, --checksum, --chown, --chmod, --link, --exclude)
 		if !strings.HasPrefix(current.Value, "--") {
-			argCount++
+			args = append(args, current.Value)
 		}
 		current = current.Next
 	}
+	argCount := len(args)
 
 	if argCount < 2 {
-		return []Rule{NewErrorRule(node, "AddMissingArguments",
+		addRules = append(addRules, NewErrorRule(node, "AddMissingArguments",
 			"ADD instruction requires at least source and destination arguments",
-			"https://docs.docker.com/reference/dockerfile/#add")}
+			"https://docs.docker.com/reference/dockerfile/#add"))
 	}
 
 	// Validate flags if present (flags are stored in node.Flags, not node.Next)
@@ -35,14 +55,27 @@ func parseADD(node *parser.Node) []Rule {
 		// Check if it's a valid flag
 		flagName := strings.Split(flag, "=")[0]
 		if !isValidADDFlag(flagName) {
-			return []Rule{NewErrorRule(node, "AddInvalidFlag",
+			addRules = append(addRules, NewErrorRule(node, "AddInvalidFlag",
 				"ADD instruction has invalid flag: "+flagName,
-				"https://docs.docker.com/reference/dockerfile/#add")}
+				"https://docs.docker.com/reference/dockerfile/#add").at(flag))
+		}
+	}
+
+	// A remote source is downloaded at build time, only --checksum makes
+	// sure it is still the file that was reviewed
+	if argCount >= 2 && !slices.ContainsFunc(node.Flags, func(flag string) bool {
+		return strings.HasPrefix(flag, "--checksum=")
+	}) {
+		for _, source := range args[:argCount-1] {
+			if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+				addRules = append(addRules, NewWarningRule(node, "AddRemoteWithoutChecksum",
+					"ADD downloads '"+source+"' without --checksum, so its content is not verified",
+					"https://docs.docker.com/reference/dockerfile/#add---checksum").at(source))
+			}
 		}
 	}
 
-	// No warnings in this file
-	return nil
+	return addRules
 }
 
 // isValidADDFlag checks if a flag is valid for ADD instruction
@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -151,6 +152,12 @@ ADD file.txt`,
 ADD --chown=user:group`,
 			expectedRules: []string{"InvalidInstruction"},
 		},
+		{
+			name: "missing destination and invalid flag",
+			dockerfile: `FROM alpine
+ADD --invalid file.txt`,
+			expectedRules: []string{"AddMissingArguments", "AddInvalidFlag"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -180,3 +187,52 @@ ADD --chown=user:group`,
 		})
 	}
 }
+
+func TestAddRemoteWithoutChecksum(t *testing.T) {
+	tests := []struct {
+		name          string
+		dockerfile    string
+		expectedRules []string
+	}{
+		{
+			name:          "HTTPS source without checksum",
+			dockerfile:    "FROM alpine\nADD https://example.com/tool.tar.gz /tmp/",
+			expectedRules: []string{"AddRemoteWithoutChecksum"},
+		},
+		{
+			name:          "every remote source is reported",
+			dockerfile:    "FROM alpine\nADD http://example.com/a.txt local.txt https://example.com/b.txt /dest/",
+			expectedRules: []string{"AddRemoteWithoutChecksum", "AddRemoteWithoutChecksum"},
+		},
+		{
+			name:          "HTTPS source with checksum",
+			dockerfile:    "FROM alpine\nADD --checksum=sha256:24454f830cdb571e2c4ad15481119c43b3cafd48dd869a9b2945d1036d1dc68d https://example.com/tool.tar.gz /tmp/",
+			expectedRules: []string{},
+		},
+		{
+			name:          "local source",
+			dockerfile:    "FROM alpine\nADD tool.tar.gz /tmp/",
+			expectedRules: []string{},
+		},
+		{
+			name:          "URL as the destination only",
+			dockerfile:    "FROM alpine\nADD https://example.com/tool.tar.gz",
+			expectedRules: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Lint(context.Background(), LintInput{Content: tt.dockerfile},
+				Options{Config: &Config{Preset: "security"}, Rules: []string{"AddRemoteWithoutChecksum"}})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedRules, getRuleCodes(result.Rules))
+		})
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		result, err := ParseDockerfile("FROM alpine\nADD https://example.com/tool.tar.gz /tmp/")
+		require.NoError(t, err)
+		require.Empty(t, result.Rules)
+	})
+}
@@ -7,6 +7,23 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("ARG", CheckMetadata{
+		Description:  "Checks the format of ARG instructions",
+		Instructions: []string{"ARG"},
+		Rules: []RuleMetadata{
+			{Code: "ArgInvalidFormat", Severity: SeverityError},
+			{Code: "ArgMissingName", Severity: SeverityError},
+			// No fix: ARG foo bar may declare two arguments rather than
+			// foo with the default bar, so ARG foo=bar could change it
+			{Code: "LegacyKeyValueFormat", Severity: SeverityWarning, BuildKit: true},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseARG(node)
+	}))
+}
+
 func parseARG(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "ARG requires at least one argument")}
@@ -15,16 +32,15 @@ func parseARG(node *parser.Node) []Rule {
 	// Extract arg configuration
 	argConfig := extractARGConfig(node)
 
-	// ERROR CHECKS - Return immediately on first error
-
-	// Validate that config is not empty
+	// Without a name there is nothing else to check
 	if strings.TrimSpace(argConfig) == "" {
 		return []Rule{NewErrorRule(node, "ArgMissingName",
 			"ARG instruction must specify at least one argument name",
 			"https://docs.docker.com/reference/dockerfile/#arg")}
 	}
 
-	// Validate ARG format (if not using legacy syntax)
+	// Validate ARG format (if not using legacy syntax). The legacy syntax is
+	// checked below, and an invalid format can't be checked further
 	if !checkARGLegacySyntax(argConfig) && !checkARGFormat(argConfig) {
 		return []Rule{NewErrorRule(node, "ArgInvalidFormat",
 			"ARG instruction must be in the format <name>[=<default value>]",
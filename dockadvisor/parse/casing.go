@@ -6,6 +6,17 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("ConsistentInstructionCasing", CheckMetadata{
+		Description: "Checks that instruction keywords use the same casing",
+		Rules: []RuleMetadata{
+			{Code: "ConsistentInstructionCasing", Severity: SeverityWarning, BuildKit: true, FileScoped: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkConsistentInstructionCasing(node)
+	}))
+}
+
 // checkConsistentInstructionCasing checks that all instruction keywords use consistent casing.
 // Instructions should be either all uppercase or all lowercase, not mixed.
 // Returns rules for instructions that don't match the predominant casing style.
@@ -59,7 +70,7 @@ func checkConsistentInstructionCasing(ast *parser.Node) []Rule {
 					Description: "Instruction '" + instruction + "' should be consistently cased as " + expectedCase,
 					Url:         "https://docs.docker.com/reference/build-checks/consistent-instruction-casing/",
 					Severity:    SeverityWarning,
-				})
+				}.atKeyword().withFix(recaseKeyword(child, expectedCase == "uppercase")))
 			}
 		}
 
@@ -88,7 +99,7 @@ func checkConsistentInstructionCasing(ast *parser.Node) []Rule {
 				Description: "Instruction '" + instruction + "' should be consistently cased as " + expectedStyle,
 				Url:         "https://docs.docker.com/reference/build-checks/consistent-instruction-casing/",
 				Severity:    SeverityWarning,
-			})
+			}.atKeyword().withFix(recaseKeyword(child, true)))
 		} else if !preferUppercase && !isLowercase {
 			expectedStyle := "lowercase"
 			rules = append(rules, Rule{
@@ -98,9 +109,18 @@ func checkConsistentInstructionCasing(ast *parser.Node) []Rule {
 				Description: "Instruction '" + instruction + "' should be consistently cased as " + expectedStyle,
 				Url:         "https://docs.docker.com/reference/build-checks/consistent-instruction-casing/",
 				Severity:    SeverityWarning,
-			})
+			}.atKeyword().withFix(recaseKeyword(child, false)))
 		}
 	}
 
 	return rules
 }
+
+// recaseKeyword returns a fix changing the keyword of an instruction to
+// uppercase or lowercase
+func recaseKeyword(node *parser.Node, uppercase bool) fix {
+	if uppercase {
+		return replaceKeyword(node, strings.ToUpper(node.Value))
+	}
+	return replaceKeyword(node, strings.ToLower(node.Value))
+}
@@ -0,0 +1,368 @@
+package parse
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// Check is a lint check. Built-in checks register themselves with Register
+// when the package is initialized; other checks can be registered the same
+// way, or run by a Linter without being registered.
+type Check interface {
+	// ID uniquely identifies the check
+	ID() string
+
+	// Metadata describes the check and the rules it reports
+	Metadata() CheckMetadata
+
+	// Run returns the findings of the check. A check whose metadata lists
+	// instructions is run once for each of those instructions with its
+	// node, any other check once with the root of the AST.
+	Run(node *parser.Node, ctx *CheckContext) []Rule
+}
+
+// CheckMetadata describes a check
+type CheckMetadata struct {
+	// Description is a short summary of what the check looks for
+	Description string
+
+	// Instructions lists the uppercase keywords of the instructions the
+	// check is run on, or nothing for a check of the whole Dockerfile
+	Instructions []string
+
+	// Rules describes the rule codes the check reports
+	Rules []RuleMetadata
+}
+
+// RuleMetadata describes a rule code
+type RuleMetadata struct {
+	Code string
+
+	// Severity is the severity the rule is reported with by default
+	Severity Severity
+
+	// BuildKit is set when the rule is also a BuildKit build check
+	BuildKit bool
+
+	// FileScoped is set when findings of the rule depend on the whole
+	// Dockerfile rather than on the lines they are reported on, e.g. a
+	// reference to an ARG declared elsewhere
+	FileScoped bool
+
+	// Options lists the names of the rule options the rule accepts
+	Options []string
+
+	// OptIn is set when the rule is off unless a preset or the configuration
+	// turns it on
+	OptIn bool
+}
+
+// CheckContext is what a check is run with besides its node
+type CheckContext struct {
+	// Content is the source of the Dockerfile
+	Content string
+
+	// AST is the root of the parsed Dockerfile
+	AST *parser.Node
+
+	// Warnings holds the warnings of the BuildKit parser
+	Warnings []parser.Warning
+
+	// Config is the configuration the Dockerfile is linted with, may be nil
+	Config *Config
+
+	// BuildArgs, Target and Platform describe the build the Dockerfile is
+	// linted for, see LintInput. BuildArgs includes the target platform
+	// args and is never nil.
+	BuildArgs map[string]string
+	Target    string
+	Platform  string
+}
+
+// Option returns the values of a rule option, or nil when it isn't set
+func (c *CheckContext) Option(code, name string) []string {
+	return c.Config.option(code, name)
+}
+
+// NewCheck returns a check that runs the given function
+func NewCheck(id string, metadata CheckMetadata, run func(node *parser.Node, ctx *CheckContext) []Rule) Check {
+	return &funcCheck{id: id, metadata: metadata, run: run}
+}
+
+type funcCheck struct {
+	id       string
+	metadata CheckMetadata
+	run      func(node *parser.Node, ctx *CheckContext) []Rule
+}
+
+func (c *funcCheck) ID() string              { return c.id }
+func (c *funcCheck) Metadata() CheckMetadata { return c.metadata }
+func (c *funcCheck) Run(node *parser.Node, ctx *CheckContext) []Rule {
+	return c.run(node, ctx)
+}
+
+// linterRules describes the rules the Linter reports itself rather than
+// through a check
+var linterRules = []RuleMetadata{
+	{Code: unusedSuppressionCode, Severity: SeverityWarning},
+}
+
+var registry struct {
+	sync.RWMutex
+	checks []Check
+	ids    map[string]bool
+}
+
+// Register adds a check to the checks a Linter runs by default. Checks run
+// in the order they were registered. It panics when the ID is empty or
+// already registered.
+func Register(check Check) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	id := check.ID()
+	if id == "" {
+		panic("parse: Register called with an empty check ID")
+	}
+	if registry.ids[id] {
+		panic(fmt.Sprintf("parse: Register called twice for check %s", id))
+	}
+	if registry.ids == nil {
+		registry.ids = map[string]bool{}
+	}
+	registry.ids[id] = true
+	registry.checks = append(registry.checks, check)
+}
+
+// RegisteredChecks returns the registered checks in registration order
+func RegisteredChecks() []Check {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Check(nil), registry.checks...)
+}
+
+// LookupCheck returns the registered check with the given ID
+func LookupCheck(id string) (Check, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, check := range registry.checks {
+		if check.ID() == id {
+			return check, true
+		}
+	}
+	return nil, false
+}
+
+// Rules returns the metadata of every rule code the registered checks
+// report, sorted by code
+func Rules() []RuleMetadata {
+	return rulesOf(RegisteredChecks())
+}
+
+// LookupRule returns the metadata of a rule code reported by a registered
+// check
+func LookupRule(code string) (RuleMetadata, bool) {
+	for _, rule := range Rules() {
+		if rule.Code == code {
+			return rule, true
+		}
+	}
+	return RuleMetadata{}, false
+}
+
+// rulesOf returns the metadata of the rule codes reported by the checks and
+// the Linter itself, sorted by code. A code reported by several checks, such
+// as InvalidInstruction, is described by the first of them.
+func rulesOf(checks []Check) []RuleMetadata {
+	seen := map[string]bool{}
+	var rules []RuleMetadata
+	add := func(rule RuleMetadata) {
+		if !seen[rule.Code] {
+			seen[rule.Code] = true
+			rules = append(rules, rule)
+		}
+	}
+
+	for _, check := range checks {
+		for _, rule := range check.Metadata().Rules {
+			add(rule)
+		}
+	}
+	for _, rule := range linterRules {
+		add(rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Code < rules[j].Code })
+	return rules
+}
+
+// Linter lints Dockerfiles with a set of checks. The zero value runs every
+// registered check with the default configuration.
+type Linter struct {
+	// Checks are the checks to run, the registered checks when empty
+	Checks []Check
+
+	// Config customizes the rules reported, may be nil
+	Config *Config
+}
+
+// checks returns the checks the linter runs
+func (l *Linter) checks() []Check {
+	if len(l.Checks) == 0 {
+		return RegisteredChecks()
+	}
+	return l.Checks
+}
+
+// Lint lints a Dockerfile, leaving out the rules the configuration disables
+// and applying its severity overrides and rule options
+func (l *Linter) Lint(dockerfileContent string) (*Result, error) {
+	result, err := l.lint(context.Background(), LintInput{Content: dockerfileContent}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result.Result, nil
+}
+
+// lint lints the Dockerfile of the input, reporting only the given rule
+// codes when there are any
+func (l *Linter) lint(ctx context.Context, input LintInput, codes []string) (*LintResult, error) {
+	checks := l.checks()
+	rules := rulesOf(checks)
+
+	config := l.Config
+	if err := config.validate(rules); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	config = config.withPreset(rules).withOptIn(rules)
+
+	for _, code := range codes {
+		if !slices.ContainsFunc(rules, func(rule RuleMetadata) bool { return rule.Code == code }) {
+			return nil, fmt.Errorf("unknown rule %q", code)
+		}
+	}
+
+	buildArgs, err := input.buildArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	dockerfileContent := input.Content
+	result, err := parser.Parse(strings.NewReader(dockerfileContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dockerfile: %v", err)
+	}
+
+	stages, err := findStages(result.AST, strings.Count(dockerfileContent, "\n")+1, input.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	checkContext := &CheckContext{
+		Content:   dockerfileContent,
+		AST:       result.AST,
+		Warnings:  result.Warnings,
+		Config:    config,
+		BuildArgs: buildArgs,
+		Target:    input.Target,
+		Platform:  input.Platform,
+	}
+
+	// Whole-file checks run first, then the instruction checks on every
+	// instruction in order
+	var parseRules []Rule
+	byInstruction := map[string][]Check{}
+	for _, check := range checks {
+		instructions := check.Metadata().Instructions
+		if len(instructions) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			parseRules = append(parseRules, check.Run(result.AST, checkContext)...)
+			continue
+		}
+		for _, instruction := range instructions {
+			instruction = strings.ToUpper(instruction)
+			byInstruction[instruction] = append(byInstruction[instruction], check)
+		}
+	}
+	for _, child := range result.AST.Children {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, check := range byInstruction[strings.ToUpper(child.Value)] {
+			parseRules = append(parseRules, check.Run(child, checkContext)...)
+		}
+	}
+
+	// Honor BuildKit's "# check=skip=...;error=true" directive
+	directive, _ := findCheckDirective(dockerfileContent)
+	parseRules = directive.apply(parseRules, rules)
+
+	// Suppressions are matched against whole instructions, so they are
+	// resolved before the rules are narrowed to their columns
+	parseRules, suppressedRules := suppressRules(result.AST, parseRules, dockerfileContent)
+
+	parseRules = config.apply(parseRules)
+	suppressedRules = config.apply(suppressedRules)
+	locateRules(parseRules, dockerfileContent)
+	locateRules(suppressedRules, dockerfileContent)
+	sortRules(parseRules)
+	sortRules(suppressedRules)
+	fingerprintRules(result.AST, dockerfileContent, parseRules, suppressedRules)
+
+	// Leave out the rules that weren't asked for and the findings in stages
+	// the target doesn't need
+	keep := func(rules []Rule) []Rule {
+		if len(codes) == 0 && input.Target == "" {
+			return rules
+		}
+		kept := rules[:0]
+		for _, rule := range rules {
+			if len(codes) != 0 && !slices.Contains(codes, rule.Code) {
+				continue
+			}
+			if inUnusedStage(rule, stages) {
+				continue
+			}
+			kept = append(kept, rule)
+		}
+		return kept
+	}
+	parseRules = keep(parseRules)
+	if suppressedRules != nil {
+		suppressedRules = keep(suppressedRules)
+	}
+
+	score, breakdown := config.score(parseRules)
+	return &LintResult{
+		Result: &Result{
+			Rules:      parseRules,
+			Score:      score,
+			Breakdown:  breakdown,
+			Suppressed: suppressedRules,
+			config:     config,
+		},
+		Filename: input.Filename,
+		Target:   input.Target,
+		Stages:   stages,
+	}, nil
+}
+
+// sortRules sorts the rules by their position in the Dockerfile so that the
+// output doesn't depend on the order the checks run in. Rules at the same
+// position, and rules outside of any line, keep the order they were reported in.
+func sortRules(rules []Rule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].StartLine != rules[j].StartLine {
+			return rules[i].StartLine < rules[j].StartLine
+		}
+		return rules[i].StartColumn < rules[j].StartColumn
+	})
+}
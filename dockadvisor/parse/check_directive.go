@@ -0,0 +1,135 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+func init() {
+	Register(NewCheck("InvalidCheckDirective", CheckMetadata{
+		Description: "Checks the BuildKit check directive",
+		Rules: []RuleMetadata{
+			{Code: "InvalidCheckDirective", Severity: SeverityWarning},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkDirectiveRules(ctx.Content)
+	}))
+}
+
+// directiveRegexp matches a parser directive line such as "# check=skip=all"
+var directiveRegexp = regexp.MustCompile(`^#\s*([a-zA-Z][a-zA-Z0-9]*)\s*=\s*(.*?)\s*$`)
+
+// checkDirective holds the options of a "# check=..." parser directive,
+// which configures BuildKit's build checks:
+//
+//	# check=skip=JSONArgsRecommended,StageNameCasing;error=true
+type checkDirective struct {
+	skipAll bool
+	skip    map[string]bool
+	error   bool
+}
+
+// findCheckDirective reads the check directive from the parser directives
+// at the top of the Dockerfile. Like BuildKit, it stops looking at the first
+// line that isn't a directive. It returns nil when there is no directive,
+// and an InvalidCheckDirective rule when the directive can't be parsed.
+func findCheckDirective(dockerfileContent string) (*checkDirective, []Rule) {
+	for i, line := range strings.Split(dockerfileContent, "\n") {
+		match := directiveRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			return nil, nil
+		}
+		if !strings.EqualFold(match[1], "check") {
+			continue
+		}
+
+		directive, err := parseCheckDirective(match[2])
+		if err != nil {
+			return nil, []Rule{{
+				StartLine:   i + 1,
+				EndLine:     i + 1,
+				Code:        "InvalidCheckDirective",
+				Description: fmt.Sprintf("Invalid check directive: %v", err),
+				Url:         "https://docs.docker.com/build/checks/#configure-checks",
+				Severity:    SeverityWarning,
+			}}
+		}
+		return directive, nil
+	}
+	return nil, nil
+}
+
+// checkDirectiveRules returns the InvalidCheckDirective rule when the check
+// directive can't be parsed
+func checkDirectiveRules(dockerfileContent string) []Rule {
+	_, rules := findCheckDirective(dockerfileContent)
+	return rules
+}
+
+// parseCheckDirective parses the semicolon-separated options of a check
+// directive. The experimental option is accepted and ignored since
+// dockadvisor has no experimental rules.
+func parseCheckDirective(value string) (*checkDirective, error) {
+	directive := &checkDirective{skip: map[string]bool{}}
+
+	for _, option := range strings.Split(value, ";") {
+		key, optionValue, ok := strings.Cut(option, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected key=value, got %q", strings.TrimSpace(option))
+		}
+		optionValue = strings.TrimSpace(optionValue)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "skip":
+			for _, code := range strings.Split(optionValue, ",") {
+				code = strings.TrimSpace(code)
+				if code == "all" {
+					directive.skipAll = true
+				} else if code != "" {
+					directive.skip[code] = true
+				}
+			}
+		case "error":
+			promote, err := strconv.ParseBool(optionValue)
+			if err != nil {
+				return nil, fmt.Errorf("error must be true or false, got %q", optionValue)
+			}
+			directive.error = promote
+		case "experimental":
+		default:
+			return nil, fmt.Errorf("unknown option %q, expected skip, error or experimental", strings.TrimSpace(key))
+		}
+	}
+
+	return directive, nil
+}
+
+// apply drops the skipped rules and, with error=true, promotes warnings to
+// errors. skip=all only covers the rules that are also BuildKit checks,
+// according to the metadata of the checks run.
+func (d *checkDirective) apply(rules []Rule, metadata []RuleMetadata) []Rule {
+	if d == nil {
+		return rules
+	}
+
+	buildKit := map[string]bool{}
+	for _, rule := range metadata {
+		buildKit[rule.Code] = rule.BuildKit
+	}
+
+	kept := rules[:0]
+	for _, rule := range rules {
+		if d.skip[rule.Code] || d.skipAll && buildKit[rule.Code] {
+			continue
+		}
+		if d.error && rule.Severity == SeverityWarning {
+			rule.Severity = SeverityError
+		}
+		kept = append(kept, rule)
+	}
+	return kept
+}
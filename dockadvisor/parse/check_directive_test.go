@@ -0,0 +1,174 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDirective(t *testing.T) {
+	tests := []struct {
+		name              string
+		dockerfileContent string
+		expectedCodes     []string
+		expectedSeverity  Severity // severity of every reported rule, when set
+	}{
+		{
+			name: "no directive",
+			dockerfileContent: `FROM alpine AS Builder
+WORKDIR app`,
+			expectedCodes: []string{"StageNameCasing", "WorkdirRelativePath"},
+		},
+		{
+			name: "skip listed checks",
+			dockerfileContent: `# check=skip=StageNameCasing,JSONArgsRecommended
+FROM alpine AS Builder
+WORKDIR app
+CMD echo hi`,
+			expectedCodes: []string{"WorkdirRelativePath"},
+		},
+		{
+			name: "skip applies to dockadvisor codes too",
+			dockerfileContent: `# check=skip=ShellRequiresJsonForm
+FROM alpine
+SHELL /bin/bash -c`,
+			expectedCodes: []string{},
+		},
+		{
+			name: "skip all only covers BuildKit checks",
+			dockerfileContent: `# check=skip=all
+FROM alpine AS Builder
+WORKDIR app
+EXPOSE 80/tcp 70000`,
+			expectedCodes: []string{"ExposePortOutOfRange"},
+		},
+		{
+			name: "error promotes warnings",
+			dockerfileContent: `# check=error=true
+FROM alpine AS Builder
+WORKDIR app`,
+			expectedCodes:    []string{"StageNameCasing", "WorkdirRelativePath"},
+			expectedSeverity: SeverityError,
+		},
+		{
+			name: "skip and error with spacing and casing",
+			dockerfileContent: `#CHECK = skip=StageNameCasing ; error=TRUE
+FROM alpine AS Builder
+WORKDIR app`,
+			expectedCodes:    []string{"WorkdirRelativePath"},
+			expectedSeverity: SeverityError,
+		},
+		{
+			name: "after other directives",
+			dockerfileContent: `# syntax=docker/dockerfile:1
+# escape=\
+# check=skip=WorkdirRelativePath
+FROM alpine
+WORKDIR app`,
+			expectedCodes: []string{},
+		},
+		{
+			name: "ignored after the first non-directive line",
+			dockerfileContent: `# Build the application
+# check=skip=WorkdirRelativePath
+FROM alpine
+WORKDIR app`,
+			expectedCodes: []string{"WorkdirRelativePath"},
+		},
+		{
+			name: "experimental is accepted",
+			dockerfileContent: `# check=experimental=all;skip=WorkdirRelativePath
+FROM alpine
+WORKDIR app`,
+			expectedCodes: []string{},
+		},
+		{
+			name: "invalid directive",
+			dockerfileContent: `# check=skip=WorkdirRelativePath;error=maybe
+FROM alpine
+WORKDIR app`,
+			expectedCodes:    []string{"WorkdirRelativePath", "InvalidCheckDirective"},
+			expectedSeverity: SeverityWarning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDockerfile(tt.dockerfileContent)
+			require.NoError(t, err)
+
+			codes := []string{}
+			for _, rule := range result.Rules {
+				codes = append(codes, rule.Code)
+				if tt.expectedSeverity != "" {
+					require.Equal(t, tt.expectedSeverity, rule.Severity, rule.Code)
+				}
+			}
+			require.ElementsMatch(t, tt.expectedCodes, codes)
+		})
+	}
+}
+
+func TestParseCheckDirective(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    *checkDirective
+		expectedErr string
+	}{
+		{
+			name:     "skip list",
+			value:    "skip=A, B",
+			expected: &checkDirective{skip: map[string]bool{"A": true, "B": true}},
+		},
+		{
+			name:     "skip all and error",
+			value:    "skip=all;error=true",
+			expected: &checkDirective{skipAll: true, skip: map[string]bool{}, error: true},
+		},
+		{
+			name:        "missing value",
+			value:       "skip",
+			expectedErr: `expected key=value, got "skip"`,
+		},
+		{
+			name:        "unknown option",
+			value:       "ignore=A",
+			expectedErr: `unknown option "ignore", expected skip, error or experimental`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, err := parseCheckDirective(tt.value)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, directive)
+		})
+	}
+}
+
+func TestCheckDirectiveSkipAll(t *testing.T) {
+	// skip=all follows the BuildKit flag of the metadata, not the URL of
+	// the rules
+	newCheck := func(code string, buildKit bool, url string) Check {
+		return NewCheck(code, CheckMetadata{
+			Instructions: []string{"FROM"},
+			Rules:        []RuleMetadata{{Code: code, Severity: SeverityWarning, BuildKit: buildKit}},
+		}, func(node *parser.Node, ctx *CheckContext) []Rule {
+			return []Rule{NewWarningRule(node, code, code, url)}
+		})
+	}
+	linter := &Linter{Checks: []Check{
+		newCheck("BuildKitCheck", true, "https://example.com/buildkit-check"),
+		newCheck("OwnCheck", false, "https://docs.docker.com/reference/build-checks/own-check/"),
+	}}
+
+	result, err := linter.Lint("# check=skip=all\nFROM alpine\n")
+	require.NoError(t, err)
+	require.Equal(t, []string{"OwnCheck"}, ruleCodes(result.Rules))
+}
@@ -0,0 +1,156 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/stretchr/testify/require"
+)
+
+// latestTagCheck is an organization-specific check as a library user would
+// write it
+var latestTagCheck = NewCheck("LatestTag", CheckMetadata{
+	Description:  "Checks that base images are pinned",
+	Instructions: []string{"FROM"},
+	Rules: []RuleMetadata{
+		{Code: "LatestTag", Severity: SeverityWarning, Options: []string{"allow"}},
+	},
+}, func(node *parser.Node, ctx *CheckContext) []Rule {
+	image := node.Next.Value
+	for _, allowed := range ctx.Option("LatestTag", "allow") {
+		if image == allowed {
+			return nil
+		}
+	}
+	if strings.HasSuffix(image, ":latest") {
+		return []Rule{NewWarningRule(node, "LatestTag", "Pin the base image to a version", "")}
+	}
+	return nil
+})
+
+func TestLinter(t *testing.T) {
+	dockerfile := `FROM alpine:latest
+FROM debian:latest AS build
+WORKDIR app`
+
+	t.Run("custom check", func(t *testing.T) {
+		linter := &Linter{Checks: []Check{latestTagCheck}}
+		result, err := linter.Lint(dockerfile)
+		require.NoError(t, err)
+		require.Equal(t, []string{"LatestTag", "LatestTag"}, ruleCodes(result.Rules))
+		require.Equal(t, []int{1, 2}, []int{result.Rules[0].StartLine, result.Rules[1].StartLine})
+		require.Equal(t, 90, result.Score)
+	})
+
+	t.Run("custom check with built-in checks", func(t *testing.T) {
+		linter := &Linter{Checks: append(RegisteredChecks(), latestTagCheck)}
+		result, err := linter.Lint(dockerfile)
+		require.NoError(t, err)
+		require.Equal(t, []string{"LatestTag", "LatestTag", "WorkdirRelativePath"}, ruleCodes(result.Rules))
+	})
+
+	t.Run("findings are sorted by position", func(t *testing.T) {
+		// Whole-file checks run before the instruction checks
+		lastLineCheck := NewCheck("LastLine", CheckMetadata{
+			Description: "Reports the last instruction",
+			Rules:       []RuleMetadata{{Code: "LastLine", Severity: SeverityWarning}},
+		}, func(node *parser.Node, ctx *CheckContext) []Rule {
+			last := node.Children[len(node.Children)-1]
+			return []Rule{NewWarningRule(last, "LastLine", "Last instruction", "")}
+		})
+		linter := &Linter{Checks: []Check{lastLineCheck, latestTagCheck}}
+		result, err := linter.Lint(dockerfile)
+		require.NoError(t, err)
+		require.Equal(t, []string{"LatestTag", "LatestTag", "LastLine"}, ruleCodes(result.Rules))
+		require.Equal(t, []int{1, 2, 3}, []int{result.Rules[0].StartLine, result.Rules[1].StartLine, result.Rules[2].StartLine})
+	})
+
+	t.Run("config applies to custom rules", func(t *testing.T) {
+		linter := &Linter{
+			Checks: []Check{latestTagCheck},
+			Config: &Config{Rules: map[string]RuleConfig{
+				"LatestTag": {Severity: SeverityError, Options: map[string][]string{"allow": {"alpine:latest"}}},
+			}},
+		}
+		result, err := linter.Lint(dockerfile)
+		require.NoError(t, err)
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, SeverityError, result.Rules[0].Severity)
+	})
+
+	t.Run("options are validated against the checks", func(t *testing.T) {
+		linter := &Linter{
+			Checks: []Check{latestTagCheck},
+			Config: &Config{Rules: map[string]RuleConfig{
+				"LatestTag": {Options: map[string][]string{"deny": {"alpine:latest"}}},
+			}},
+		}
+		_, err := linter.Lint(dockerfile)
+		require.EqualError(t, err, `invalid config: rule LatestTag: unknown option "deny", expected one of: allow`)
+	})
+
+	t.Run("presets cover custom rules", func(t *testing.T) {
+		linter := &Linter{
+			Checks: []Check{latestTagCheck},
+			Config: &Config{Preset: "minimal"},
+		}
+		result, err := linter.Lint(dockerfile)
+		require.NoError(t, err)
+		require.Empty(t, result.Rules)
+	})
+
+	t.Run("zero value runs the registered checks", func(t *testing.T) {
+		result, err := (&Linter{}).Lint(dockerfile)
+		require.NoError(t, err)
+
+		expected, err := ParseDockerfile(dockerfile)
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	check, ok := LookupCheck("WORKDIR")
+	require.True(t, ok)
+	require.Equal(t, []string{"WORKDIR"}, check.Metadata().Instructions)
+
+	require.PanicsWithValue(t, "parse: Register called twice for check WORKDIR", func() {
+		Register(check)
+	})
+	require.Panics(t, func() {
+		Register(NewCheck("", CheckMetadata{}, nil))
+	})
+
+	_, ok = LookupCheck("LatestTag")
+	require.False(t, ok, "checks are only registered explicitly")
+}
+
+func TestRules(t *testing.T) {
+	rules := Rules()
+	for i, rule := range rules {
+		require.True(t, rule.Severity.Valid(), "rule %s has an invalid severity", rule.Code)
+		if i > 0 {
+			require.Less(t, rules[i-1].Code, rule.Code, "rules should be sorted and unique")
+		}
+	}
+
+	rule, ok := LookupRule("UndefinedVar")
+	require.True(t, ok)
+	require.Equal(t, RuleMetadata{Code: "UndefinedVar", Severity: SeverityError, BuildKit: true, FileScoped: true}, rule)
+
+	rule, ok = LookupRule(unusedSuppressionCode)
+	require.True(t, ok, "rules reported by the linter itself are listed")
+	require.Equal(t, SeverityWarning, rule.Severity)
+
+	_, ok = LookupRule("LatestTag")
+	require.False(t, ok)
+}
+
+func ruleCodes(rules []Rule) []string {
+	codes := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		codes = append(codes, rule.Code)
+	}
+	return codes
+}
@@ -7,6 +7,20 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("CMD", CheckMetadata{
+		Description:  "Checks the command of CMD instructions",
+		Instructions: []string{"CMD"},
+		Rules: []RuleMetadata{
+			{Code: "CmdInvalidExecForm", Severity: SeverityError},
+			{Code: "CmdMissingCommand", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseCMD(node)
+	}))
+}
+
 func parseCMD(node *parser.Node) []Rule {
 	// Special case: CMD [] is valid (empty array as default params to ENTRYPOINT)
 	// Check Original field to see if it's just "CMD []"
@@ -15,14 +29,13 @@ func parseCMD(node *parser.Node) []Rule {
 		return []Rule{invalidInstructionRule(node, "CMD requires at least one argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// Extract command
 	command := extractCMDCommand(node)
 	if command == "" {
 		command = trimmedOriginal
 	}
 
-	// Validate that command is not empty
+	// Without a command there is nothing else to check
 	if strings.TrimSpace(command) == "" {
 		return []Rule{NewErrorRule(node, "CmdMissingCommand",
 			"CMD instruction must specify a command to execute",
@@ -0,0 +1,165 @@
+package parse
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Config customizes the rules reported by ParseDockerfileWithConfig.
+// The zero value reports every rule with its default severity.
+type Config struct {
+	// Preset is the name of a built-in preset the rule settings apply on
+	// top of, see Presets
+	Preset string `json:"preset,omitempty" yaml:"preset"`
+
+	// Rules holds per-rule settings keyed by rule code
+	Rules map[string]RuleConfig `json:"rules,omitempty" yaml:"rules"`
+
+	// Scoring customizes how findings cost points
+	Scoring *Scoring `json:"scoring,omitempty" yaml:"scoring"`
+}
+
+// RuleConfig holds the settings of a single rule
+type RuleConfig struct {
+	// Enabled turns the rule off when set to false
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled"`
+
+	// Severity overrides the default severity of the rule
+	Severity Severity `json:"severity,omitempty" yaml:"severity"`
+
+	// Options holds rule-specific settings, see RuleMetadata.Options
+	Options map[string][]string `json:"options,omitempty" yaml:"options"`
+
+	// Weight overrides the points each finding of the rule costs
+	Weight *int `json:"weight,omitempty" yaml:"weight"`
+
+	// Cap limits the points lost to all findings of the rule, 0 means no
+	// limit. It overrides the scoring cap.
+	Cap *int `json:"cap,omitempty" yaml:"cap"`
+}
+
+// Valid reports whether s is one of the known severities
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityFatal, SeverityError, SeverityWarning, SeverityInfo, SeverityHint:
+		return true
+	}
+	return false
+}
+
+// Validate checks the preset, rule codes, severities and options of the
+// configuration against the registered checks
+func (c *Config) Validate() error {
+	return c.validate(Rules())
+}
+
+// validate checks the configuration against the rules a linter reports
+func (c *Config) validate(rules []RuleMetadata) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Preset != "" {
+		if _, ok := presets[c.Preset]; !ok {
+			return unknownPresetError(c.Preset)
+		}
+	}
+
+	ruleOptions := make(map[string][]string, len(rules))
+	for _, rule := range rules {
+		ruleOptions[rule.Code] = rule.Options
+	}
+
+	codes := make([]string, 0, len(c.Rules))
+	for code := range c.Rules {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if _, ok := ruleOptions[code]; !ok {
+			return unknownRuleError(code, rules)
+		}
+
+		ruleConfig := c.Rules[code]
+		if ruleConfig.Severity != "" && !ruleConfig.Severity.Valid() {
+			return fmt.Errorf("rule %s: invalid severity %q, expected fatal, error, warning, info or hint", code, ruleConfig.Severity)
+		}
+		if ruleConfig.Weight != nil && *ruleConfig.Weight < 0 {
+			return fmt.Errorf("rule %s: weight must not be negative, got %d", code, *ruleConfig.Weight)
+		}
+		if ruleConfig.Cap != nil && *ruleConfig.Cap < 0 {
+			return fmt.Errorf("rule %s: cap must not be negative, got %d", code, *ruleConfig.Cap)
+		}
+
+		for name := range ruleConfig.Options {
+			if !slices.Contains(ruleOptions[code], name) {
+				if len(ruleOptions[code]) == 0 {
+					return fmt.Errorf("rule %s: unknown option %q, the rule has no options", code, name)
+				}
+				return fmt.Errorf("rule %s: unknown option %q, expected one of: %s", code, name, strings.Join(ruleOptions[code], ", "))
+			}
+		}
+	}
+
+	if c.Scoring != nil {
+		for severity, weight := range c.Scoring.Weights {
+			if !severity.Valid() {
+				return fmt.Errorf("scoring: invalid severity %q in weights, expected fatal, error or warning", severity)
+			}
+			if !severity.scored() {
+				return fmt.Errorf("scoring: %s findings don't cost points and can't have a weight", severity)
+			}
+			if weight < 0 {
+				return fmt.Errorf("scoring: %s weight must not be negative, got %d", severity, weight)
+			}
+		}
+		if c.Scoring.Cap < 0 {
+			return fmt.Errorf("scoring: cap must not be negative, got %d", c.Scoring.Cap)
+		}
+	}
+
+	return nil
+}
+
+// unknownRuleError reports a rule code that none of the rules has, with the
+// code it differs from only by case when there is one
+func unknownRuleError(code string, rules []RuleMetadata) error {
+	for _, rule := range rules {
+		if strings.EqualFold(rule.Code, code) {
+			return fmt.Errorf("unknown rule %q, did you mean %s?", code, rule.Code)
+		}
+	}
+	return fmt.Errorf("unknown rule %q", code)
+}
+
+// option returns the values of a rule option, or nil when it isn't set
+func (c *Config) option(code, name string) []string {
+	if c == nil {
+		return nil
+	}
+	return c.Rules[code].Options[name]
+}
+
+// apply drops the rules disabled by the configuration and overrides the
+// severity of the others
+func (c *Config) apply(rules []Rule) []Rule {
+	if c == nil || len(c.Rules) == 0 {
+		return rules
+	}
+
+	kept := rules[:0]
+	for _, rule := range rules {
+		ruleConfig, ok := c.Rules[rule.Code]
+		if ok && ruleConfig.Enabled != nil && !*ruleConfig.Enabled {
+			continue
+		}
+		if ok && ruleConfig.Severity != "" {
+			rule.Severity = ruleConfig.Severity
+		}
+		kept = append(kept, rule)
+	}
+	return kept
+}
@@ -0,0 +1,203 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	negative := -1
+	disabled := false
+
+	tests := []struct {
+		name        string
+		config      *Config
+		expectedErr string
+	}{
+		{
+			name:   "nil config",
+			config: nil,
+		},
+		{
+			name: "valid settings",
+			config: &Config{Rules: map[string]RuleConfig{
+				"JSONArgsRecommended":   {Severity: SeverityError},
+				"SecretsUsedInArgOrEnv": {Options: map[string][]string{"tokens": {"dsn"}, "allow": {"example"}}},
+				"RunInvalidNetworkFlag": {Options: map[string][]string{"networks": {"bridge"}}},
+			}},
+		},
+		{
+			name:        "unknown preset",
+			config:      &Config{Preset: "lenient"},
+			expectedErr: `unknown preset "lenient", expected one of: buildkit-parity, minimal, recommended, security, strict`,
+		},
+		{
+			name: "unknown rule",
+			config: &Config{Rules: map[string]RuleConfig{
+				"WorkdirRelativePaths": {Enabled: &disabled},
+			}},
+			expectedErr: `unknown rule "WorkdirRelativePaths"`,
+		},
+		{
+			name: "rule code with the wrong case",
+			config: &Config{Rules: map[string]RuleConfig{
+				"JsonArgsRecommended": {Severity: SeverityError},
+			}},
+			expectedErr: `unknown rule "JsonArgsRecommended", did you mean JSONArgsRecommended?`,
+		},
+		{
+			name: "invalid severity",
+			config: &Config{Rules: map[string]RuleConfig{
+				"JSONArgsRecommended": {Severity: "critical"},
+			}},
+			expectedErr: `rule JSONArgsRecommended: invalid severity "critical", expected fatal, error, warning, info or hint`,
+		},
+		{
+			name: "unknown option",
+			config: &Config{Rules: map[string]RuleConfig{
+				"SecretsUsedInArgOrEnv": {Options: map[string][]string{"token": {"dsn"}}},
+			}},
+			expectedErr: `rule SecretsUsedInArgOrEnv: unknown option "token", expected one of: allow, tokens`,
+		},
+		{
+			name: "negative rule weight",
+			config: &Config{Rules: map[string]RuleConfig{
+				"WorkdirRelativePath": {Weight: &negative},
+			}},
+			expectedErr: "rule WorkdirRelativePath: weight must not be negative, got -1",
+		},
+		{
+			name:        "invalid scoring severity",
+			config:      &Config{Scoring: &Scoring{Weights: map[Severity]int{"critical": 50}}},
+			expectedErr: `scoring: invalid severity "critical" in weights, expected fatal, error or warning`,
+		},
+		{
+			name:        "info weight",
+			config:      &Config{Scoring: &Scoring{Weights: map[Severity]int{SeverityInfo: 1}}},
+			expectedErr: "scoring: info findings don't cost points and can't have a weight",
+		},
+		{
+			name:        "negative scoring cap",
+			config:      &Config{Scoring: &Scoring{Cap: -5}},
+			expectedErr: "scoring: cap must not be negative, got -5",
+		},
+		{
+			name: "option on a rule without options",
+			config: &Config{Rules: map[string]RuleConfig{
+				"WorkdirRelativePath": {Options: map[string][]string{"paths": {"app"}}},
+			}},
+			expectedErr: `rule WorkdirRelativePath: unknown option "paths", the rule has no options`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestParseDockerfileWithConfig(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		name              string
+		dockerfileContent string
+		config            *Config
+		expectedCodes     []string
+		expectedSeverity  Severity // severity of the first rule, when set
+		expectedScore     int
+	}{
+		{
+			name:              "nil config reports every rule",
+			dockerfileContent: "FROM alpine\nWORKDIR app\nMAINTAINER me\n",
+			expectedCodes:     []string{"WorkdirRelativePath", "MaintainerDeprecated"},
+			expectedScore:     90,
+		},
+		{
+			name:              "disabled rule is left out of the score",
+			dockerfileContent: "FROM alpine\nWORKDIR app\nMAINTAINER me\n",
+			config: &Config{Rules: map[string]RuleConfig{
+				"MaintainerDeprecated": {Enabled: &disabled},
+			}},
+			expectedCodes: []string{"WorkdirRelativePath"},
+			expectedScore: 95,
+		},
+		{
+			name:              "severity override changes the score",
+			dockerfileContent: "FROM alpine\nWORKDIR app\n",
+			config: &Config{Rules: map[string]RuleConfig{
+				"WorkdirRelativePath": {Severity: SeverityError},
+			}},
+			expectedCodes:    []string{"WorkdirRelativePath"},
+			expectedSeverity: SeverityError,
+			expectedScore:    85,
+		},
+		{
+			name:              "extra secret token",
+			dockerfileContent: "FROM alpine\nENV SENTRY_DSN=value\n",
+			config: &Config{Rules: map[string]RuleConfig{
+				"SecretsUsedInArgOrEnv": {Options: map[string][]string{"tokens": {"dsn"}}},
+			}},
+			expectedCodes: []string{"SecretsUsedInArgOrEnv"},
+			expectedScore: 95,
+		},
+		{
+			name:              "extra allow token",
+			dockerfileContent: "FROM alpine\nENV EXAMPLE_TOKEN=value\n",
+			config: &Config{Rules: map[string]RuleConfig{
+				"SecretsUsedInArgOrEnv": {Options: map[string][]string{"allow": {"example"}}},
+			}},
+			expectedCodes: []string{},
+			expectedScore: 100,
+		},
+		{
+			name:              "extra allowed network",
+			dockerfileContent: "FROM alpine\nRUN --network=bridge make\n",
+			config: &Config{Rules: map[string]RuleConfig{
+				"RunInvalidNetworkFlag": {Options: map[string][]string{"networks": {"bridge"}}},
+			}},
+			expectedCodes: []string{},
+			expectedScore: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDockerfileWithConfig(tt.dockerfileContent, tt.config)
+			require.NoError(t, err)
+
+			codes := []string{}
+			for _, rule := range result.Rules {
+				codes = append(codes, rule.Code)
+			}
+			require.ElementsMatch(t, tt.expectedCodes, codes)
+			if tt.expectedSeverity != "" {
+				require.Equal(t, tt.expectedSeverity, result.Rules[0].Severity)
+			}
+			require.Equal(t, tt.expectedScore, result.Score)
+		})
+	}
+
+	t.Run("network error lists the extra networks", func(t *testing.T) {
+		config := &Config{Rules: map[string]RuleConfig{
+			"RunInvalidNetworkFlag": {Options: map[string][]string{"networks": {"bridge"}}},
+		}}
+		result, err := ParseDockerfileWithConfig("FROM alpine\nRUN --network=overlay make\n", config)
+		require.NoError(t, err)
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, "RUN --network flag must be one of: default, none, host, bridge. Got: 'overlay'", result.Rules[0].Description)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		config := &Config{Rules: map[string]RuleConfig{"WorkdirRelativePath": {Severity: "critical"}}}
+		_, err := ParseDockerfileWithConfig("FROM alpine\n", config)
+		require.ErrorContains(t, err, "invalid config")
+	})
+}
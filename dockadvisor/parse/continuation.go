@@ -2,8 +2,21 @@ package parse
 
 import (
 	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("NoEmptyContinuation", CheckMetadata{
+		Description: "Checks for empty lines in continued instructions",
+		Rules: []RuleMetadata{
+			{Code: "NoEmptyContinuation", Severity: SeverityWarning, BuildKit: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkEmptyContinuations(ctx.Content)
+	}))
+}
+
 // checkEmptyContinuations scans the dockerfile content for empty continuation lines.
 // Empty continuation lines are empty lines following a newline escape character (\).
 // These are deprecated and will generate errors in future versions of Docker.
@@ -51,7 +64,7 @@ func checkEmptyContinuations(dockerfileContent string) []Rule {
 				Description: "Empty continuation line found. Empty lines following a backslash continuation are deprecated and will cause errors in future Docker versions.",
 				Url:         "https://docs.docker.com/reference/build-checks/no-empty-continuation/",
 				Severity:    SeverityWarning,
-			})
+			}.withFix(removeEmptyLines(lines, i+1)))
 
 			// Skip the empty line to avoid duplicate reports
 			i++
@@ -60,3 +73,13 @@ func checkEmptyContinuations(dockerfileContent string) []Rule {
 
 	return rules
 }
+
+// removeEmptyLines returns the fix removing the run of empty lines starting
+// at the 0-indexed line start
+func removeEmptyLines(lines []string, start int) fix {
+	end := start
+	for end+1 < len(lines) && strings.TrimSpace(lines[end+1]) == "" {
+		end++
+	}
+	return removeLines(start+1, end+1)
+}
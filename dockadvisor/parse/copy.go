@@ -6,12 +6,29 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("COPY", CheckMetadata{
+		Description:  "Checks the flags and arguments of COPY instructions",
+		Instructions: []string{"COPY"},
+		Rules: []RuleMetadata{
+			{Code: "CopyInvalidFlag", Severity: SeverityError},
+			{Code: "CopyMissingArguments", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseCOPY(node)
+	}))
+}
+
 func parseCOPY(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "COPY requires at least source and destination arguments")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
+	// The arguments and every flag are checked independently so that every
+	// problem is reported at once
+	var copyRules []Rule
+
 	// COPY requires at least 2 arguments: source and destination
 	// Count non-flag arguments
 	argCount := 0
@@ -25,9 +42,9 @@ func parseCOPY(node *parser.Node) []Rule {
 	}
 
 	if argCount < 2 {
-		return []Rule{NewErrorRule(node, "CopyMissingArguments",
+		copyRules = append(copyRules, NewErrorRule(node, "CopyMissingArguments",
 			"COPY instruction requires at least source and destination arguments",
-			"https://docs.docker.com/reference/dockerfile/#copy")}
+			"https://docs.docker.com/reference/dockerfile/#copy"))
 	}
 
 	// Validate flags if present (flags are stored in node.Flags, not node.Next)
@@ -35,14 +52,13 @@ func parseCOPY(node *parser.Node) []Rule {
 		// Check if it's a valid flag
 		flagName := strings.Split(flag, "=")[0]
 		if !isValidCOPYFlag(flagName) {
-			return []Rule{NewErrorRule(node, "CopyInvalidFlag",
+			copyRules = append(copyRules, NewErrorRule(node, "CopyInvalidFlag",
 				"COPY instruction has invalid flag: "+flagName,
-				"https://docs.docker.com/reference/dockerfile/#copy")}
+				"https://docs.docker.com/reference/dockerfile/#copy").at(flag))
 		}
 	}
 
-	// No warnings in this file
-	return nil
+	return copyRules
 }
 
 // isValidCOPYFlag checks if a flag is valid for COPY instruction
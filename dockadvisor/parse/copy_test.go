@@ -137,6 +137,11 @@ func TestParseCOPY(t *testing.T) {
 			dockerfileContent: `COPY --invalid file.txt /dest/`,
 			expectedRules:     []string{"CopyInvalidFlag"},
 		},
+		{
+			name:              "missing destination and invalid flag",
+			dockerfileContent: `COPY --invalid file.txt`,
+			expectedRules:     []string{"CopyMissingArguments", "CopyInvalidFlag"},
+		},
 	}
 
 	for _, tt := range tests {
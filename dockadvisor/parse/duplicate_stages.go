@@ -6,6 +6,17 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("DuplicateStageName", CheckMetadata{
+		Description: "Checks that stage names are unique",
+		Rules: []RuleMetadata{
+			{Code: "DuplicateStageName", Severity: SeverityError, BuildKit: true, FileScoped: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkDuplicateStageNames(node)
+	}))
+}
+
 // checkDuplicateStageNames checks that all stage names in the Dockerfile are unique.
 // Stage names are compared case-insensitively since Docker treats stage names in a case-insensitive manner.
 // Returns rules for any duplicate stage name declarations.
@@ -50,7 +61,7 @@ func checkDuplicateStageNames(ast *parser.Node) []Rule {
 					Description: "Duplicate stage name '" + stage.originalName + "', stage names should be unique",
 					Url:         "https://docs.docker.com/reference/build-checks/duplicate-stage-name/",
 					Severity:    SeverityError,
-				})
+				}.at(stage.originalName))
 			}
 		}
 	}
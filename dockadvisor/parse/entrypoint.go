@@ -7,12 +7,25 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("ENTRYPOINT", CheckMetadata{
+		Description:  "Checks the command of ENTRYPOINT instructions",
+		Instructions: []string{"ENTRYPOINT"},
+		Rules: []RuleMetadata{
+			{Code: "EntrypointInvalidExecForm", Severity: SeverityError},
+			{Code: "EntrypointMissingCommand", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseENTRYPOINT(node)
+	}))
+}
+
 func parseENTRYPOINT(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "ENTRYPOINT requires at least one argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// Extract command - use Original field for accurate detection
 	trimmedOriginal := strings.TrimSpace(strings.TrimPrefix(node.Original, node.Value))
 	command := extractENTRYPOINTCommand(node)
@@ -20,7 +33,7 @@ func parseENTRYPOINT(node *parser.Node) []Rule {
 		command = trimmedOriginal
 	}
 
-	// Validate that command is not empty
+	// Without a command there is nothing else to check
 	if strings.TrimSpace(command) == "" {
 		return []Rule{NewErrorRule(node, "EntrypointMissingCommand",
 			"ENTRYPOINT instruction must specify a command to execute",
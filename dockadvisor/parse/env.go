@@ -6,6 +6,21 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("ENV", CheckMetadata{
+		Description:  "Checks the format of ENV instructions",
+		Instructions: []string{"ENV"},
+		Rules: []RuleMetadata{
+			{Code: "EnvInvalidFormat", Severity: SeverityError},
+			{Code: "EnvMissingKeyValue", Severity: SeverityError},
+			{Code: "LegacyKeyValueFormat", Severity: SeverityWarning, BuildKit: true},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseENV(node)
+	}))
+}
+
 func parseENV(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "ENV requires at least one argument")}
@@ -14,16 +29,15 @@ func parseENV(node *parser.Node) []Rule {
 	// Extract env configuration
 	envConfig := extractENVConfig(node)
 
-	// ERROR CHECKS - Return immediately on first error
-
-	// Validate that config is not empty
+	// Without a key there is nothing else to check
 	if strings.TrimSpace(envConfig) == "" {
 		return []Rule{NewErrorRule(node, "EnvMissingKeyValue",
 			"ENV instruction must specify at least one key=value pair",
 			"https://docs.docker.com/reference/dockerfile/#env")}
 	}
 
-	// Validate env key=value format (if not using legacy syntax)
+	// Validate env key=value format (if not using legacy syntax). The legacy
+	// syntax is checked below, and an invalid format can't be checked further
 	if !checkENVLegacySyntax(envConfig) && !checkENVFormat(envConfig) {
 		return []Rule{NewErrorRule(node, "EnvInvalidFormat",
 			"ENV instruction must be in the format <key>=<value> [<key>=<value>...]",
@@ -37,7 +51,7 @@ func parseENV(node *parser.Node) []Rule {
 	if checkENVLegacySyntax(envConfig) {
 		envRules = append(envRules, NewWarningRule(node, "LegacyKeyValueFormat",
 			"Legacy key/value format with whitespace separator should not be used. Use ENV key=value format instead",
-			"https://docs.docker.com/reference/build-checks/legacy-key-value-format/"))
+			"https://docs.docker.com/reference/build-checks/legacy-key-value-format/").withFix(fixENVLegacySyntax(node, envConfig)...))
 	}
 
 	return envRules
@@ -73,6 +87,26 @@ func checkENVLegacySyntax(config string) bool {
 	return hasSpace && !hasEquals
 }
 
+// fixENVLegacySyntax returns the fix rewriting a legacy ENV <key> <value>
+// to ENV <key>=<value>, quoting the value when it has whitespace. There is
+// none for an instruction spanning several lines or a value with quotes or
+// escapes, whose meaning could change.
+func fixENVLegacySyntax(node *parser.Node, config string) []fix {
+	if node.StartLine != node.EndLine || strings.ContainsAny(config, "\"'\\") {
+		return nil
+	}
+
+	i := strings.IndexAny(config, " \t")
+	if i < 0 {
+		return nil
+	}
+	key, value := config[:i], strings.TrimSpace(config[i:])
+	if strings.ContainsAny(value, " \t") {
+		value = `"` + value + `"`
+	}
+	return []fix{replaceText(node, config, key+"="+value)}
+}
+
 // checkENVFormat validates the ENV instruction format
 func checkENVFormat(config string) bool {
 	config = strings.TrimSpace(config)
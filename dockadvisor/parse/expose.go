@@ -7,51 +7,61 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("EXPOSE", CheckMetadata{
+		Description:  "Checks the ports of EXPOSE instructions",
+		Instructions: []string{"EXPOSE"},
+		Rules: []RuleMetadata{
+			{Code: "ExposeInvalidFormat", Severity: SeverityError, BuildKit: true},
+			{Code: "ExposeInvalidProtocol", Severity: SeverityError},
+			{Code: "ExposePortOutOfRange", Severity: SeverityError},
+			{Code: "ExposeProtoCasing", Severity: SeverityWarning, BuildKit: true},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseEXPOSE(node)
+	}))
+}
+
 func parseEXPOSE(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "EXPOSE requires at least one argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
-	// Check all arguments in the EXPOSE instruction
-	current := node.Next
-	for current != nil {
+	// Every port is checked, and each check is independent, so that every
+	// problem is reported at once
+	var exposeRules []Rule
+
+	for current := node.Next; current != nil; current = current.Next {
 		if !checkExposeFormat(current.Value) {
-			return []Rule{NewErrorRule(node, "ExposeInvalidFormat",
+			exposeRules = append(exposeRules, NewErrorRule(node, "ExposeInvalidFormat",
 				"EXPOSE instruction should not define an IP address or host-port mapping, found '"+current.Value+"'",
-				"https://docs.docker.com/reference/build-checks/expose-invalid-format/")}
+				"https://docs.docker.com/reference/build-checks/expose-invalid-format/").at(current.Value))
 		}
 
 		// Check if port number is within valid range (0-65535)
 		if !checkExposePortRange(current.Value) {
-			return []Rule{NewErrorRule(node, "ExposePortOutOfRange",
+			exposeRules = append(exposeRules, NewErrorRule(node, "ExposePortOutOfRange",
 				"Port number in EXPOSE instruction is outside valid UNIX port range (0-65535): '"+current.Value+"'",
-				"https://en.wikipedia.org/wiki/List_of_TCP_and_UDP_port_numbers")}
+				"https://en.wikipedia.org/wiki/List_of_TCP_and_UDP_port_numbers").at(current.Value))
 		}
 
 		// Check if protocol is valid (only tcp or udp)
-		if !checkExposeValidProtocol(current.Value) {
-			return []Rule{NewErrorRule(node, "ExposeInvalidProtocol",
+		validProtocol := checkExposeValidProtocol(current.Value)
+		if !validProtocol {
+			exposeRules = append(exposeRules, NewErrorRule(node, "ExposeInvalidProtocol",
 				"Invalid protocol in EXPOSE instruction '"+current.Value+"', only 'tcp' and 'udp' are supported",
-				"https://docs.docker.com/reference/dockerfile/#expose")}
+				"https://docs.docker.com/reference/dockerfile/#expose").at(current.Value))
 		}
 
-		current = current.Next
-	}
-
-	// WARNING CHECKS - Collect warnings
-	var exposeRules []Rule
-
-	current = node.Next
-	for current != nil {
-		// Check protocol casing if present
-		if !checkExposeProtoCasing(current.Value) {
+		// Check protocol casing if present. Lowercasing an invalid protocol
+		// wouldn't make it valid, so it is only reported as invalid
+		if validProtocol && !checkExposeProtoCasing(current.Value) {
 			exposeRules = append(exposeRules, NewWarningRule(node, "ExposeProtoCasing",
 				"Defined protocol '"+current.Value+"' in EXPOSE instruction should be lowercase",
-				"https://docs.docker.com/reference/build-checks/expose-proto-casing/"))
+				"https://docs.docker.com/reference/build-checks/expose-proto-casing/").at(current.Value).
+				withFix(replaceText(node, current.Value, strings.ToLower(current.Value))))
 		}
-
-		current = current.Next
 	}
 
 	return exposeRules
@@ -149,6 +159,8 @@ func checkExposeProtoCasing(portSpec string) bool {
 //   - "65535" -> true (maximum valid port)
 //   - "0" -> true (minimum valid port)
 //   - "80000" -> false (exceeds maximum)
+//   - "8000-9000/tcp" -> true (valid port range)
+//   - "8000-90000" -> false (end of the range exceeds maximum)
 //   - "-1" -> false (below minimum)
 //   - "abc" -> false (not a number)
 func checkExposePortRange(portSpec string) bool {
@@ -164,11 +176,23 @@ func checkExposePortRange(portSpec string) bool {
 	// Try to parse the port as an integer
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
+		// A port range such as 8000-9000 is valid if both of its ends are
+		if start, end, ok := strings.Cut(portStr, "-"); ok {
+			startPort, startErr := strconv.Atoi(start)
+			endPort, endErr := strconv.Atoi(end)
+			if startErr == nil && endErr == nil {
+				return isValidPort(startPort) && isValidPort(endPort)
+			}
+		}
 		// If it's not a valid integer, return true (validation handled elsewhere)
 		// This could be a variable reference like $PORT
 		return true
 	}
 
-	// Check if port is within valid range (0-65535)
+	return isValidPort(port)
+}
+
+// isValidPort checks if port is within the valid UNIX port range (0-65535)
+func isValidPort(port int) bool {
 	return port >= 0 && port <= 65535
 }
@@ -483,13 +483,15 @@ func TestParseEXPOSE(t *testing.T) {
 
 		rules := parseEXPOSE(node)
 
-		// Should flag first invalid protocol (443/https) and return immediately
-		require.Len(t, rules, 1, "expected 1 rule for the first invalid protocol")
+		// Should flag every invalid protocol
+		require.Len(t, rules, 2, "expected 1 rule per invalid protocol")
 		require.Equal(t, "ExposeInvalidProtocol", rules[0].Code)
 		require.Contains(t, rules[0].Description, "443/https")
+		require.Equal(t, "ExposeInvalidProtocol", rules[1].Code)
+		require.Contains(t, rules[1].Description, "9999/sctp")
 	})
 
-	t.Run("returns both ExposeInvalidProtocol and ExposeProtoCasing when protocol is invalid and uppercase", func(t *testing.T) {
+	t.Run("returns only ExposeInvalidProtocol when protocol is invalid and uppercase", func(t *testing.T) {
 		node := &parser.Node{
 			Value:     "EXPOSE",
 			StartLine: 3,
@@ -501,10 +503,27 @@ func TestParseEXPOSE(t *testing.T) {
 
 		rules := parseEXPOSE(node)
 
-		// Should flag invalid protocol (error) and return immediately, not check casing (warning)
-		require.Len(t, rules, 1, "expected 1 rule for invalid protocol error")
+		// Lowercasing an invalid protocol wouldn't fix it
+		require.Len(t, rules, 1, "expected only an invalid protocol error")
 		require.Equal(t, "ExposeInvalidProtocol", rules[0].Code)
 	})
+
+	t.Run("returns ExposeProtoCasing alongside other errors of a valid protocol", func(t *testing.T) {
+		node := &parser.Node{
+			Value:     "EXPOSE",
+			StartLine: 3,
+			EndLine:   3,
+			Next: &parser.Node{
+				Value: "80000/TCP",
+			},
+		}
+
+		rules := parseEXPOSE(node)
+
+		require.Len(t, rules, 2, "expected an out of range error and a casing warning")
+		require.Equal(t, "ExposePortOutOfRange", rules[0].Code)
+		require.Equal(t, "ExposeProtoCasing", rules[1].Code)
+	})
 }
 
 func TestCheckExposePortRange(t *testing.T) {
@@ -590,6 +609,32 @@ func TestCheckExposePortRange(t *testing.T) {
 			portSpec: "70000/udp",
 			expected: false,
 		},
+		// Port ranges
+		{
+			name:     "valid port range",
+			portSpec: "8000-9000",
+			expected: true,
+		},
+		{
+			name:     "valid port range with protocol",
+			portSpec: "8000-9000/udp",
+			expected: true,
+		},
+		{
+			name:     "port range end exceeds maximum",
+			portSpec: "8000-90000",
+			expected: false,
+		},
+		{
+			name:     "port range start exceeds maximum",
+			portSpec: "70000-70010/tcp",
+			expected: false,
+		},
+		{
+			name:     "port range with variables",
+			portSpec: "$START-$END",
+			expected: true,
+		},
 		// Variable references (should pass - not validated as numbers)
 		{
 			name:     "variable reference",
@@ -638,6 +683,11 @@ func TestParseEXPOSEWithPortRange(t *testing.T) {
 			dockerfileContent: "EXPOSE 80 443 8080",
 			expectedRules:     []string{},
 		},
+		{
+			name:              "valid port range",
+			dockerfileContent: "EXPOSE 8000-9000/tcp",
+			expectedRules:     []string{},
+		},
 		// Invalid port ranges
 		{
 			name:              "port exceeds maximum - 80000",
@@ -664,10 +714,15 @@ func TestParseEXPOSEWithPortRange(t *testing.T) {
 			dockerfileContent: "EXPOSE 80 80000 443",
 			expectedRules:     []string{"ExposePortOutOfRange"},
 		},
+		{
+			name:              "port range exceeds maximum",
+			dockerfileContent: "EXPOSE 8000-90000",
+			expectedRules:     []string{"ExposePortOutOfRange"},
+		},
 		{
 			name:              "multiple invalid ports",
 			dockerfileContent: "EXPOSE 80000 100000",
-			expectedRules:     []string{"ExposePortOutOfRange"},
+			expectedRules:     []string{"ExposePortOutOfRange", "ExposePortOutOfRange"},
 		},
 	}
 
@@ -0,0 +1,113 @@
+package parse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// instructionContext is what identifies an instruction independently of
+// its line: the stage it belongs to, its keyword and its arguments
+type instructionContext struct {
+	startLine, endLine int
+	stage              string
+	keyword            string
+	args               string
+}
+
+// fingerprintRules sets the Fingerprint of the rules in every list. A
+// fingerprint is a hash of the rule code, the stage name (or index when the
+// stage has no name), the instruction keyword and its arguments with
+// whitespace collapsed, so it stays the same when lines are added or removed
+// elsewhere. Identical rules are told apart by an occurrence counter in line
+// order. Rules outside of an instruction use the text of their line.
+func fingerprintRules(ast *parser.Node, dockerfileContent string, lists ...[]Rule) {
+	instructions := instructionContexts(ast)
+	lines := strings.Split(dockerfileContent, "\n")
+
+	var rules []*Rule
+	for _, list := range lists {
+		for i := range list {
+			rules = append(rules, &list[i])
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].StartLine != rules[j].StartLine {
+			return rules[i].StartLine < rules[j].StartLine
+		}
+		return rules[i].StartColumn < rules[j].StartColumn
+	})
+
+	occurrences := map[string]int{}
+	for _, rule := range rules {
+		var context instructionContext
+		for _, instruction := range instructions {
+			if instruction.startLine <= rule.StartLine && rule.StartLine <= instruction.endLine {
+				context = instruction
+				break
+			}
+		}
+		if context.keyword == "" && rule.StartLine >= 1 && rule.StartLine <= len(lines) {
+			context.args = collapseSpace(lines[rule.StartLine-1])
+		}
+
+		key := strings.Join([]string{rule.Code, context.stage, context.keyword, context.args}, "\x00")
+		sum := sha256.Sum256([]byte(key + "\x00" + strconv.Itoa(occurrences[key])))
+		occurrences[key]++
+
+		rule.Fingerprint = hex.EncodeToString(sum[:16])
+	}
+}
+
+// instructionContexts returns the context of every instruction. Stages are
+// named after their lowercased AS name, or their index when they have none;
+// instructions before the first FROM have no stage.
+func instructionContexts(ast *parser.Node) []instructionContext {
+	if ast == nil {
+		return nil
+	}
+
+	var contexts []instructionContext
+	stage := ""
+	stageIndex := 0
+	for _, child := range ast.Children {
+		keyword := strings.ToLower(child.Value)
+		if keyword == "from" {
+			stage = strconv.Itoa(stageIndex)
+			stageIndex++
+
+			var args []string
+			for current := child.Next; current != nil; current = current.Next {
+				args = append(args, current.Value)
+			}
+			if len(args) == 3 && strings.EqualFold(args[1], "AS") {
+				stage = strings.ToLower(args[2])
+			}
+		}
+
+		// The arguments are the original text without the keyword
+		fields := strings.Fields(child.Original)
+		args := ""
+		if len(fields) > 1 {
+			args = strings.Join(fields[1:], " ")
+		}
+
+		contexts = append(contexts, instructionContext{
+			startLine: child.StartLine,
+			endLine:   child.EndLine,
+			stage:     stage,
+			keyword:   keyword,
+			args:      args,
+		})
+	}
+	return contexts
+}
+
+// collapseSpace collapses runs of whitespace into single spaces
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,107 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fingerprints(t *testing.T, dockerfileContent string) []string {
+	t.Helper()
+
+	result, err := ParseDockerfile(dockerfileContent)
+	require.NoError(t, err)
+
+	var fingerprints []string
+	for _, rule := range result.Rules {
+		require.Len(t, rule.Fingerprint, 32)
+		fingerprints = append(fingerprints, rule.Fingerprint)
+	}
+	return fingerprints
+}
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		same   bool
+	}{
+		{
+			name: "lines added above",
+			before: `FROM alpine AS build
+WORKDIR app`,
+			after: `# syntax=docker/dockerfile:1
+
+FROM alpine AS build
+RUN apk add curl
+WORKDIR app`,
+			same: true,
+		},
+		{
+			name: "whitespace changes",
+			before: `FROM alpine AS build
+WORKDIR app`,
+			after: `FROM alpine AS build
+WORKDIR    app  `,
+			same: true,
+		},
+		{
+			name: "different arguments",
+			before: `FROM alpine AS build
+WORKDIR app`,
+			after: `FROM alpine AS build
+WORKDIR src`,
+			same: false,
+		},
+		{
+			name: "different stage",
+			before: `FROM alpine AS build
+WORKDIR app`,
+			after: `FROM alpine AS test
+WORKDIR app`,
+			same: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := fingerprints(t, tt.before)
+			after := fingerprints(t, tt.after)
+			require.Len(t, before, 1)
+			require.Len(t, after, 1)
+			if tt.same {
+				require.Equal(t, before[0], after[0])
+			} else {
+				require.NotEqual(t, before[0], after[0])
+			}
+		})
+	}
+}
+
+func TestFingerprintOccurrences(t *testing.T) {
+	found := fingerprints(t, `FROM alpine
+WORKDIR app
+WORKDIR app`)
+	require.Len(t, found, 2)
+	require.NotEqual(t, found[0], found[1], "identical findings should get distinct fingerprints")
+
+	// Removing the first finding gives the second one its fingerprint
+	require.Equal(t, found[:1], fingerprints(t, `FROM alpine
+WORKDIR app`))
+}
+
+func TestFingerprintSuppressed(t *testing.T) {
+	result, err := ParseDockerfile(`FROM alpine
+# dockadvisor ignore=WorkdirRelativePath
+WORKDIR app
+WORKDIR app`)
+	require.NoError(t, err)
+	require.Len(t, result.Rules, 1)
+	require.Len(t, result.Suppressed, 1)
+
+	// Suppressing a finding doesn't change the fingerprints of the others
+	require.Equal(t, fingerprints(t, `FROM alpine
+WORKDIR app
+WORKDIR app`)[1], result.Rules[0].Fingerprint)
+}
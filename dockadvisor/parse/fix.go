@@ -0,0 +1,226 @@
+package parse
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// Edit replaces a range of the Dockerfile source with new text. Lines and
+// columns are 1-based like those of a Rule, and the end is exclusive: an
+// edit from line 3 column 1 to line 4 column 1 with no new text removes the
+// whole of line 3.
+type Edit struct {
+	StartLine   int    `json:"startLine"`
+	StartColumn int    `json:"startColumn"`
+	EndLine     int    `json:"endLine"`
+	EndColumn   int    `json:"endColumn"`
+	NewText     string `json:"newText"`
+}
+
+// fix is an edit a check requests, resolved to an Edit against the
+// Dockerfile source by locateRules like the target of a rule
+type fix struct {
+	startLine int // the lines of the instruction the target is in
+	endLine   int
+	target    target
+	newText   string
+
+	// instruction replaces the whole instruction, from its keyword to the
+	// end of its last line, rather than the target
+	instruction bool
+
+	// lines removes the lines from startLine to endLine, line breaks
+	// included, rather than replacing the target
+	lines bool
+}
+
+// withFix adds fixes that resolve the rule. The fixes are applied together,
+// or not at all when one of them can't be located.
+func (r Rule) withFix(fixes ...fix) Rule {
+	r.fixes = append(r.fixes, fixes...)
+	return r
+}
+
+// replaceText returns a fix replacing the first occurrence of text as a
+// whole word within the instruction of node, ignoring its keyword
+func replaceText(node *parser.Node, text, newText string) fix {
+	return fix{startLine: node.StartLine, endLine: node.EndLine, target: target{text: text}, newText: newText}
+}
+
+// replaceKeyword returns a fix replacing the keyword of the instruction of
+// node
+func replaceKeyword(node *parser.Node, newText string) fix {
+	return fix{startLine: node.StartLine, endLine: node.EndLine, target: target{keyword: true}, newText: newText}
+}
+
+// replaceInstruction returns a fix replacing the whole instruction of node
+func replaceInstruction(node *parser.Node, newText string) fix {
+	return fix{startLine: node.StartLine, endLine: node.EndLine, instruction: true, newText: newText}
+}
+
+// removeLines returns a fix removing the lines from start to end
+func removeLines(start, end int) fix {
+	return fix{startLine: start, endLine: end, lines: true}
+}
+
+// resolveFixes resolves the fixes of a rule to edits, or returns nil when
+// one of them can't be located
+func resolveFixes(fixes []fix, lines []string) []Edit {
+	edits := make([]Edit, 0, len(fixes))
+	for _, f := range fixes {
+		edit, ok := resolveFix(f, lines)
+		if !ok {
+			return nil
+		}
+		edits = append(edits, edit)
+	}
+	return edits
+}
+
+// resolveFix resolves a single fix to an edit
+func resolveFix(f fix, lines []string) (Edit, bool) {
+	if f.startLine < 1 || f.endLine < f.startLine || f.endLine > len(lines) {
+		return Edit{}, false
+	}
+
+	switch {
+	case f.lines:
+		// The line break of the last line is removed with it, so there
+		// must be a line after it
+		if f.endLine == len(lines) {
+			return Edit{}, false
+		}
+		return Edit{StartLine: f.startLine, StartColumn: 1, EndLine: f.endLine + 1, EndColumn: 1, NewText: f.newText}, true
+	case f.instruction:
+		start, _ := keywordSpan(lines[f.startLine-1])
+		last := lines[f.endLine-1]
+		return Edit{
+			StartLine:   f.startLine,
+			StartColumn: utf8.RuneCountInString(lines[f.startLine-1][:start]) + 1,
+			EndLine:     f.endLine,
+			EndColumn:   utf8.RuneCountInString(strings.TrimRight(last, " \t")) + 1,
+			NewText:     f.newText,
+		}, true
+	}
+
+	located := Rule{StartLine: f.startLine, EndLine: f.endLine, target: f.target}
+	locateRule(&located, lines)
+	if located.StartColumn == 0 {
+		return Edit{}, false
+	}
+	return Edit{
+		StartLine:   located.StartLine,
+		StartColumn: located.StartColumn,
+		EndLine:     located.EndLine,
+		EndColumn:   located.EndColumn,
+		NewText:     f.newText,
+	}, true
+}
+
+// ApplyFixes applies the fixes of the rules to the Dockerfile source and
+// returns the fixed source with the number of rules fixed. Fixes are taken
+// in the order of their first edit; a fix with an edit overlapping an edit
+// of a fix already taken is skipped, so that linting the fixed source and
+// applying its fixes again picks it up.
+func ApplyFixes(dockerfileContent string, rules []Rule) (string, int) {
+	var fixes [][]Edit
+	for _, rule := range rules {
+		if len(rule.Fix) > 0 {
+			fixes = append(fixes, rule.Fix)
+		}
+	}
+	sort.SliceStable(fixes, func(i, j int) bool {
+		return editBefore(fixes[i][0], fixes[j][0])
+	})
+
+	// offsets holds the byte offset of the start of each line
+	offsets := []int{0}
+	for i, b := range []byte(dockerfileContent) {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+
+	var taken []span
+	fixed := 0
+	for _, edits := range fixes {
+		spans := make([]span, 0, len(edits))
+		for _, edit := range edits {
+			start, ok := byteOffset(dockerfileContent, offsets, edit.StartLine, edit.StartColumn)
+			end, endOK := byteOffset(dockerfileContent, offsets, edit.EndLine, edit.EndColumn)
+			if !ok || !endOK || end < start {
+				spans = nil
+				break
+			}
+			spans = append(spans, span{start, end, edit.NewText})
+		}
+		if spans == nil || overlaps(spans, taken) {
+			continue
+		}
+		taken = append(taken, spans...)
+		fixed++
+	}
+
+	// Apply the edits from the end so that the offsets of the others stay
+	// valid
+	sort.Slice(taken, func(i, j int) bool { return taken[i].start > taken[j].start })
+	for _, s := range taken {
+		dockerfileContent = dockerfileContent[:s.start] + s.newText + dockerfileContent[s.end:]
+	}
+	return dockerfileContent, fixed
+}
+
+// span is an edit resolved to the byte range [start, end) of the source
+type span struct {
+	start, end int
+	newText    string
+}
+
+// overlaps reports whether any of the spans overlaps any of the taken
+// ones. Edits starting at the same offset overlap too, since the order of
+// two insertions there would be ambiguous.
+func overlaps(spans, taken []span) bool {
+	for _, s := range spans {
+		for _, t := range taken {
+			if s.start < t.end && t.start < s.end || s.start == t.start {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// editBefore reports whether edit a starts before edit b
+func editBefore(a, b Edit) bool {
+	if a.StartLine != b.StartLine {
+		return a.StartLine < b.StartLine
+	}
+	return a.StartColumn < b.StartColumn
+}
+
+// byteOffset returns the byte offset of a 1-based line and column, where a
+// column counts runes and may be just past the end of the line
+func byteOffset(content string, offsets []int, line, column int) (int, bool) {
+	if line < 1 || line > len(offsets) || column < 1 {
+		return 0, false
+	}
+	start := offsets[line-1]
+	end := len(content)
+	if line < len(offsets) {
+		end = offsets[line] - 1
+	}
+	text := strings.TrimRight(content[start:end], "\r")
+
+	offset := 0
+	for range column - 1 {
+		if offset >= len(text) {
+			return 0, false
+		}
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return start + offset, true
+}
@@ -0,0 +1,178 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFixes checks that the fix of each fixable rule resolves the finding
+func TestFixes(t *testing.T) {
+	tests := []struct {
+		name              string
+		dockerfileContent string
+		code              string
+		expectedContent   string // the content with the fixes of the rules with code applied
+	}{
+		{
+			name:              "instruction casing follows the majority",
+			dockerfileContent: "FROM alpine\nrun echo hi\nRUN echo bye\n",
+			code:              "ConsistentInstructionCasing",
+			expectedContent:   "FROM alpine\nRUN echo hi\nRUN echo bye\n",
+		},
+		{
+			name:              "mixed case instruction",
+			dockerfileContent: "from alpine\nRun echo hi\n",
+			code:              "ConsistentInstructionCasing",
+			expectedContent:   "from alpine\nrun echo hi\n",
+		},
+		{
+			name:              "AS follows the casing of FROM",
+			dockerfileContent: "FROM alpine as builder\nfrom alpine AS runtime\n",
+			code:              "FromAsCasing",
+			expectedContent:   "FROM alpine AS builder\nfrom alpine as runtime\n",
+		},
+		{
+			name:              "no AS casing fix for a mixed case FROM",
+			dockerfileContent: "From alpine AS builder\n",
+			code:              "FromAsCasing",
+			expectedContent:   "From alpine AS builder\n",
+		},
+		{
+			name:              "stage name and its references",
+			dockerfileContent: "FROM golang AS Builder\nRUN --mount=type=cache,from=BUILDER,target=/go make\nFROM Builder AS test\nFROM alpine\nCOPY --from=Builder /app /app\nCOPY --from=builder /lib /lib\n",
+			code:              "StageNameCasing",
+			expectedContent:   "FROM golang AS builder\nRUN --mount=type=cache,from=builder,target=/go make\nFROM builder AS test\nFROM alpine\nCOPY --from=builder /app /app\nCOPY --from=builder /lib /lib\n",
+		},
+		{
+			name:              "stage references stop at a stage of the same name",
+			dockerfileContent: "FROM golang AS Builder\nFROM alpine AS builder\nCOPY --from=Builder /app /app\n",
+			code:              "StageNameCasing",
+			expectedContent:   "FROM golang AS builder\nFROM alpine AS builder\nCOPY --from=Builder /app /app\n",
+		},
+		{
+			name:              "EXPOSE protocol",
+			dockerfileContent: "FROM alpine\nEXPOSE 80/TCP 53/Udp\n",
+			code:              "ExposeProtoCasing",
+			expectedContent:   "FROM alpine\nEXPOSE 80/tcp 53/udp\n",
+		},
+		{
+			name:              "legacy ENV",
+			dockerfileContent: "FROM alpine\nENV PATH /usr/local/bin:$PATH\nENV GREETING hello world\n",
+			code:              "LegacyKeyValueFormat",
+			expectedContent:   "FROM alpine\nENV PATH=/usr/local/bin:$PATH\nENV GREETING=\"hello world\"\n",
+		},
+		{
+			name:              "no legacy ENV fix for a quoted value",
+			dockerfileContent: "FROM alpine\nENV GREETING \"hello\"\n",
+			code:              "LegacyKeyValueFormat",
+			expectedContent:   "FROM alpine\nENV GREETING \"hello\"\n",
+		},
+		{
+			name:              "no legacy ARG fix",
+			dockerfileContent: "FROM alpine\nARG VERSION 1.0\n",
+			code:              "LegacyKeyValueFormat",
+			expectedContent:   "FROM alpine\nARG VERSION 1.0\n",
+		},
+		{
+			name:              "empty continuation lines",
+			dockerfileContent: "FROM alpine\nRUN apk add \\\n\n   \n    curl\n",
+			code:              "NoEmptyContinuation",
+			expectedContent:   "FROM alpine\nRUN apk add \\\n    curl\n",
+		},
+		{
+			name:              "MAINTAINER becomes a LABEL",
+			dockerfileContent: "FROM alpine\nMAINTAINER Jane Doe <jane@example.com>\n",
+			code:              "MaintainerDeprecated",
+			expectedContent:   "FROM alpine\nLABEL org.opencontainers.image.authors=\"Jane Doe <jane@example.com>\"\n",
+		},
+		{
+			name:              "lowercase maintainer",
+			dockerfileContent: "from alpine\nmaintainer jane\n",
+			code:              "MaintainerDeprecated",
+			expectedContent:   "from alpine\nlabel org.opencontainers.image.authors=\"jane\"\n",
+		},
+		{
+			name:              "no MAINTAINER fix for a name with a variable",
+			dockerfileContent: "FROM alpine\nMAINTAINER $AUTHOR\n",
+			code:              "MaintainerDeprecated",
+			expectedContent:   "FROM alpine\nMAINTAINER $AUTHOR\n",
+		},
+		{
+			name:              "CRLF line endings are kept",
+			dockerfileContent: "FROM alpine\r\nexpose 80/TCP\r\n",
+			code:              "ExposeProtoCasing",
+			expectedContent:   "FROM alpine\r\nexpose 80/tcp\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDockerfile(tt.dockerfileContent)
+			require.NoError(t, err)
+
+			var rules []Rule
+			for _, rule := range result.Rules {
+				if rule.Code == tt.code {
+					rules = append(rules, rule)
+				}
+			}
+			require.NotEmpty(t, rules, "expected a %s finding", tt.code)
+
+			fixed, _ := ApplyFixes(tt.dockerfileContent, rules)
+			require.Equal(t, tt.expectedContent, fixed)
+
+			if fixed != tt.dockerfileContent {
+				result, err = ParseDockerfile(fixed)
+				require.NoError(t, err)
+				for _, rule := range result.Rules {
+					require.NotEqual(t, tt.code, rule.Code, "the fixed Dockerfile should not be reported")
+				}
+			}
+		})
+	}
+}
+
+func TestFixEdits(t *testing.T) {
+	result, err := ParseDockerfile("FROM alpine\nEXPOSE 80/TCP\nRUN echo hi\n")
+	require.NoError(t, err)
+
+	for _, rule := range result.Rules {
+		if rule.Code == "ExposeProtoCasing" {
+			require.Equal(t, []Edit{{StartLine: 2, StartColumn: 8, EndLine: 2, EndColumn: 14, NewText: "80/tcp"}}, rule.Fix)
+		} else {
+			require.Empty(t, rule.Fix, "%s has no fix", rule.Code)
+		}
+	}
+}
+
+func TestApplyFixesOverlap(t *testing.T) {
+	content := "FROM alpine\nmaintainer jane\nRUN echo hi\n"
+	result, err := ParseDockerfile(content)
+	require.NoError(t, err)
+
+	// The casing and MAINTAINER fixes both start at the keyword, so only
+	// the first is applied
+	fixed, count := ApplyFixes(content, result.Rules)
+	require.Equal(t, 1, count)
+	require.Equal(t, "FROM alpine\nMAINTAINER jane\nRUN echo hi\n", fixed)
+
+	result, err = ParseDockerfile(fixed)
+	require.NoError(t, err)
+	fixed, count = ApplyFixes(fixed, result.Rules)
+	require.Equal(t, 1, count)
+	require.Equal(t, "FROM alpine\nLABEL org.opencontainers.image.authors=\"jane\"\nRUN echo hi\n", fixed)
+}
+
+func TestApplyFixesInvalidEdits(t *testing.T) {
+	content := "FROM alpine\n"
+	rules := []Rule{
+		{Code: "A", Fix: []Edit{{StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 2, NewText: "x"}}},
+		{Code: "B", Fix: []Edit{{StartLine: 1, StartColumn: 6, EndLine: 1, EndColumn: 30, NewText: "x"}}},
+		{Code: "C", Fix: []Edit{{StartLine: 1, StartColumn: 1, EndLine: 1, EndColumn: 5, NewText: "from"}}},
+	}
+
+	fixed, count := ApplyFixes(content, rules)
+	require.Equal(t, 1, count, "edits outside of the content are skipped")
+	require.Equal(t, "from alpine\n", fixed)
+}
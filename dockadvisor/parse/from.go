@@ -7,7 +7,73 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
-func parseFROM(node *parser.Node) []Rule {
+func init() {
+	Register(NewCheck("FROM", CheckMetadata{
+		Description:  "Checks the image, platform and stage name of FROM instructions",
+		Instructions: []string{"FROM"},
+		Rules: []RuleMetadata{
+			{Code: "FromAsCasing", Severity: SeverityWarning, BuildKit: true},
+			{Code: "FromInvalidImageReference", Severity: SeverityError},
+			{Code: "FromInvalidPlatform", Severity: SeverityError},
+			{Code: "FromInvalidStageName", Severity: SeverityError},
+			{Code: "FromMissingImage", Severity: SeverityError},
+			{Code: "RedundantTargetPlatform", Severity: SeverityWarning, BuildKit: true},
+			{Code: "ReservedStageName", Severity: SeverityError, BuildKit: true},
+			{Code: "StageNameCasing", Severity: SeverityWarning, BuildKit: true},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseFROM(node, ctx.AST)
+	}))
+	Register(NewCheck("FromUnpinnedImage", CheckMetadata{
+		Description:  "Checks that base images are pinned to a digest",
+		Instructions: []string{"FROM"},
+		Rules: []RuleMetadata{
+			{Code: "FromUnpinnedImage", Severity: SeverityWarning, OptIn: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkFromUnpinnedImage(node, ctx.AST)
+	}))
+}
+
+// checkFromUnpinnedImage reports a base image without a digest, since the
+// image a tag points to can be replaced. scratch, earlier stages and images
+// given by a variable are left alone. ast is the root of the Dockerfile; it
+// may be nil.
+func checkFromUnpinnedImage(node *parser.Node, ast *parser.Node) []Rule {
+	imageRef, _, _ := extractFromComponents(node)
+	if imageRef == "" || strings.EqualFold(imageRef, "scratch") || strings.ContainsAny(imageRef, "$@") {
+		return nil
+	}
+	if ast != nil {
+		for _, child := range ast.Children {
+			if child == node {
+				break
+			}
+			if strings.ToUpper(child.Value) != "FROM" {
+				continue
+			}
+			if _, stageName, _ := extractFromComponents(child); stageName != "" && strings.EqualFold(stageName, imageRef) {
+				return nil
+			}
+		}
+	}
+
+	// The tag follows the last colon of the last path component, the one
+	// before it may be the port of the registry
+	_, tag, tagged := strings.Cut(imageRef[strings.LastIndex(imageRef, "/")+1:], ":")
+	description := "Base image '" + imageRef + "' is not pinned to a digest, so the image its tag points to can change. Add @sha256:<digest>"
+	if !tagged || tag == "latest" {
+		description = "Base image '" + imageRef + "' uses the latest tag, which changes with every release. Pin it to a version and a digest"
+	}
+	return []Rule{NewWarningRule(node, "FromUnpinnedImage", description,
+		"https://docs.docker.com/build/building/best-practices/#pin-base-image-versions").at(imageRef)}
+}
+
+// parseFROM checks a FROM instruction. ast is the root of the Dockerfile,
+// whose later instructions referencing the stage are fixed along with its
+// name; it may be nil.
+func parseFROM(node *parser.Node, ast *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "FROM requires at least one argument")}
 	}
@@ -16,59 +82,60 @@ func parseFROM(node *parser.Node) []Rule {
 	// Format: FROM [--platform=<platform>] <image>[:<tag>|@<digest>] [AS <name>]
 	imageRef, stageName, platformFlag := extractFromComponents(node)
 
-	// ERROR CHECKS - Return immediately on first error
-	// Validate image reference is not empty
+	// Without an image there is nothing else to check
 	if strings.TrimSpace(imageRef) == "" {
 		return []Rule{NewErrorRule(node, "FromMissingImage",
 			"FROM instruction must specify an image reference",
 			"https://docs.docker.com/reference/dockerfile/#from")}
 	}
 
+	// The image, platform and stage name are checked independently so that
+	// every problem is reported at once
+	var fromRules []Rule
+
 	// Validate image reference format
-	if imageRef != "" && !checkImageReferenceFormat(imageRef) {
-		return []Rule{NewErrorRule(node, "FromInvalidImageReference",
+	if !checkImageReferenceFormat(imageRef) {
+		fromRules = append(fromRules, NewErrorRule(node, "FromInvalidImageReference",
 			"FROM instruction has invalid image reference format: '"+imageRef+"'",
-			"https://docs.docker.com/reference/dockerfile/#from")}
+			"https://docs.docker.com/reference/dockerfile/#from").at(imageRef))
 	}
 
 	// Validate --platform flag format if present
 	// Note: Platform flag constant check is now handled globally in checkPlatformFlagConstDisallowed
 	// to allow constant platforms in multi-stage builds where stages are referenced
 	if platformFlag != "" && !checkPlatformFormat(platformFlag) {
-		return []Rule{NewErrorRule(node, "FromInvalidPlatform",
+		fromRules = append(fromRules, NewErrorRule(node, "FromInvalidPlatform",
 			"FROM instruction has invalid --platform flag format: '"+platformFlag+"'",
-			"https://docs.docker.com/reference/dockerfile/#from")}
+			"https://docs.docker.com/reference/dockerfile/#from").at("--platform="+platformFlag))
 	}
 
 	// Validate stage name format if present
 	if stageName != "" && !checkStageNameFormat(stageName) {
-		return []Rule{NewErrorRule(node, "FromInvalidStageName",
+		fromRules = append(fromRules, NewErrorRule(node, "FromInvalidStageName",
 			"FROM instruction AS stage name is invalid: '"+stageName+"'. Stage names must start with a letter or underscore and contain only alphanumeric characters, underscores, hyphens, and dots.",
-			"https://docs.docker.com/reference/dockerfile/#from")}
+			"https://docs.docker.com/reference/dockerfile/#from").at(stageName))
 	}
 
 	// Check if stage name is a reserved word
 	if stageName != "" && checkReservedStageName(stageName) {
-		return []Rule{NewErrorRule(node, "ReservedStageName",
+		fromRules = append(fromRules, NewErrorRule(node, "ReservedStageName",
 			"'"+stageName+"' is reserved and should not be used as a stage name",
-			"https://docs.docker.com/reference/build-checks/reserved-stage-name/")}
+			"https://docs.docker.com/reference/build-checks/reserved-stage-name/").at(stageName))
 	}
 
-	// WARNING CHECKS - Collect warnings
-	var fromRules []Rule
-
 	// Check if platform flag is redundant (using $TARGETPLATFORM)
 	if platformFlag != "" && checkRedundantTargetPlatform(platformFlag) {
 		fromRules = append(fromRules, NewWarningRule(node, "RedundantTargetPlatform",
 			"Setting platform to predefined $TARGETPLATFORM in FROM is redundant as this is the default behavior",
-			"https://docs.docker.com/reference/build-checks/redundant-target-platform/"))
+			"https://docs.docker.com/reference/build-checks/redundant-target-platform/").at("--platform="+platformFlag))
 	}
 
 	// Check if stage name uses lowercase casing
 	if stageName != "" && !checkStageNameCasing(stageName) {
 		fromRules = append(fromRules, NewWarningRule(node, "StageNameCasing",
 			"Stage name '"+stageName+"' should be lowercase",
-			"https://docs.docker.com/reference/build-checks/stage-name-casing/"))
+			"https://docs.docker.com/reference/build-checks/stage-name-casing/").at(stageName).
+			withFix(renameStage(node, ast, stageName)...))
 	}
 
 	// While Dockerfile keywords can be either uppercase or lowercase, mixing case styles is not recommended for readability. This rule reports violations where mixed case style occurs for a FROM instruction with an AS keyword declaring a stage name.
@@ -76,7 +143,8 @@ func parseFROM(node *parser.Node) []Rule {
 	if !checkFromAsCasing(node.Value, node.Original) {
 		fromRules = append(fromRules, NewWarningRule(node, "FromAsCasing",
 			"FROM instruction with AS keyword uses inconsistent casing. Ensure that both FROM and AS keywords use the same casing style (either both uppercase or both lowercase) for better readability.",
-			"https://docs.docker.com/reference/build-checks/from-as-casing/"))
+			"https://docs.docker.com/reference/build-checks/from-as-casing/").at(fromAsKeyword(node)).
+			withFix(recaseFromAs(node)...))
 	}
 
 	return fromRules
@@ -315,6 +383,80 @@ func checkStageNameCasing(stageName string) bool {
 	return stageName == strings.ToLower(stageName)
 }
 
+// fromAsKeyword returns the AS keyword of a FROM instruction as written,
+// or an empty string when there is none
+func fromAsKeyword(node *parser.Node) string {
+	if node.Next == nil || node.Next.Next == nil || strings.ToUpper(node.Next.Next.Value) != "AS" {
+		return ""
+	}
+	return node.Next.Next.Value
+}
+
+// recaseFromAs returns the fix changing the AS keyword of a FROM instruction
+// to the casing of its FROM keyword, or none when that is mixed case
+func recaseFromAs(node *parser.Node) []fix {
+	switch node.Value {
+	case strings.ToUpper(node.Value):
+		return []fix{replaceText(node, fromAsKeyword(node), "AS")}
+	case strings.ToLower(node.Value):
+		return []fix{replaceText(node, fromAsKeyword(node), "as")}
+	}
+	return nil
+}
+
+// renameStage returns the fixes changing a stage name to lowercase, both
+// where the FROM instruction of node declares it and where the instructions
+// after it refer to it: as the image of a FROM, in COPY --from and in RUN
+// --mount=from
+func renameStage(node, ast *parser.Node, stageName string) []fix {
+	name := strings.ToLower(stageName)
+	fixes := []fix{replaceText(node, stageName, name)}
+	if ast == nil {
+		return fixes
+	}
+
+	for _, child := range ast.Children {
+		if child.StartLine <= node.StartLine {
+			continue
+		}
+		switch strings.ToUpper(child.Value) {
+		case "FROM":
+			if child.Next != nil && child.Next.Value != name && strings.EqualFold(child.Next.Value, name) {
+				fixes = append(fixes, replaceText(child, child.Next.Value, name))
+			}
+			// A stage declaring the same name shadows this one after it
+			if _, other, _ := extractFromComponents(child); strings.EqualFold(other, name) {
+				return fixes
+			}
+		case "COPY":
+			for _, flag := range child.Flags {
+				if from, ok := strings.CutPrefix(flag, "--from="); ok && from != name && strings.EqualFold(from, name) {
+					fixes = append(fixes, replaceText(child, flag, "--from="+name))
+				}
+			}
+		case "RUN":
+			for _, flag := range child.Flags {
+				mount, ok := strings.CutPrefix(flag, "--mount=")
+				if !ok {
+					continue
+				}
+				fields := strings.Split(mount, ",")
+				renamed := false
+				for i, field := range fields {
+					if from, ok := strings.CutPrefix(field, "from="); ok && from != name && strings.EqualFold(from, name) {
+						fields[i] = "from=" + name
+						renamed = true
+					}
+				}
+				if renamed {
+					fixes = append(fixes, replaceText(child, flag, "--mount="+strings.Join(fields, ",")))
+				}
+			}
+		}
+	}
+	return fixes
+}
+
 // checkFromAsCasing checks if the FROM instruction with AS keyword uses consistent casing.
 // Returns true if the casing is consistent, false otherwise.
 // While Dockerfile keywords can be either uppercase or lowercase, mixing case styles is not
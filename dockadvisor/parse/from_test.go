@@ -1,8 +1,10 @@
 package parse
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
 	"github.com/stretchr/testify/require"
 )
 
@@ -732,6 +734,67 @@ func TestCheckStageNameCasing(t *testing.T) {
 	}
 }
 
+func TestCheckFromUnpinnedImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		dockerfile  string
+		description string // description of the finding on the last FROM, empty for none
+	}{
+		{
+			name:        "tagged image",
+			dockerfile:  "FROM node:20",
+			description: "Base image 'node:20' is not pinned to a digest, so the image its tag points to can change. Add @sha256:<digest>",
+		},
+		{
+			name:        "untagged image",
+			dockerfile:  "FROM alpine",
+			description: "Base image 'alpine' uses the latest tag, which changes with every release. Pin it to a version and a digest",
+		},
+		{
+			name:        "latest tag",
+			dockerfile:  "FROM alpine:latest",
+			description: "Base image 'alpine:latest' uses the latest tag, which changes with every release. Pin it to a version and a digest",
+		},
+		{
+			name:        "registry with a port",
+			dockerfile:  "FROM localhost:5000/app",
+			description: "Base image 'localhost:5000/app' uses the latest tag, which changes with every release. Pin it to a version and a digest",
+		},
+		{
+			name:       "pinned to a digest",
+			dockerfile: "FROM node:20@sha256:a4d1de4c7339eabcf78a90137dfd551b798829e3ef3e399e0036ac454afa1291",
+		},
+		{
+			name:       "scratch",
+			dockerfile: "FROM scratch",
+		},
+		{
+			name:       "variable",
+			dockerfile: "ARG BASE=alpine\nFROM $BASE",
+		},
+		{
+			name:       "earlier stage",
+			dockerfile: "FROM golang@sha256:a4d1de4c7339eabcf78a90137dfd551b798829e3ef3e399e0036ac454afa1291 AS build\nFROM BUILD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(strings.NewReader(tt.dockerfile))
+			require.NoError(t, err)
+			last := result.AST.Children[len(result.AST.Children)-1]
+
+			rules := checkFromUnpinnedImage(last, result.AST)
+			if tt.description == "" {
+				require.Empty(t, rules)
+			} else {
+				require.Len(t, rules, 1)
+				require.Equal(t, tt.description, rules[0].Description)
+			}
+		})
+	}
+}
+
 func TestParseFROM(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -811,6 +874,16 @@ FROM base:${CODE_VERSION}`,
 			dockerfileContent: `FROM debian:latest AS builder@v1`,
 			expectedRules:     []string{"FromInvalidStageName"},
 		},
+		{
+			name:              "FROM with invalid platform and invalid stage name",
+			dockerfileContent: `FROM --platform=linux/invalidarch debian:latest AS 1builder`,
+			expectedRules:     []string{"FromPlatformFlagConstDisallowed", "FromInvalidPlatform", "FromInvalidStageName"},
+		},
+		{
+			name:              "FROM with invalid image reference and invalid stage name",
+			dockerfileContent: `FROM debian@sha256@abc AS 1builder`,
+			expectedRules:     []string{"FromInvalidImageReference", "FromInvalidStageName"},
+		},
 		{
 			name:              "FROM with mixed casing",
 			dockerfileContent: `FROM debian:latest as builder`,
@@ -844,7 +917,7 @@ FROM base:${CODE_VERSION}`,
 		{
 			name:              "FROM with reserved stage name 'SCRATCH'",
 			dockerfileContent: `FROM alpine AS SCRATCH`,
-			expectedRules:     []string{"ReservedStageName"},
+			expectedRules:     []string{"ReservedStageName", "StageNameCasing"},
 		},
 		{
 			name:              "FROM with reserved stage name 'context'",
@@ -854,12 +927,12 @@ FROM base:${CODE_VERSION}`,
 		{
 			name:              "FROM with reserved stage name 'CONTEXT'",
 			dockerfileContent: `FROM debian:latest AS CONTEXT`,
-			expectedRules:     []string{"ReservedStageName"},
+			expectedRules:     []string{"ReservedStageName", "StageNameCasing"},
 		},
 		{
 			name:              "FROM with reserved stage name 'Context' (mixed case)",
 			dockerfileContent: `FROM ubuntu:22.04 AS Context`,
-			expectedRules:     []string{"ReservedStageName"},
+			expectedRules:     []string{"ReservedStageName", "StageNameCasing"},
 		},
 		// Platform flag tests
 		{
@@ -6,12 +6,24 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("HEALTHCHECK", CheckMetadata{
+		Description:  "Checks the command of HEALTHCHECK instructions",
+		Instructions: []string{"HEALTHCHECK"},
+		Rules: []RuleMetadata{
+			{Code: "HealthcheckMissingCmd", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseHEALTHCHECK(node)
+	}))
+}
+
 func parseHEALTHCHECK(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "HEALTHCHECK requires arguments")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// HEALTHCHECK has two forms:
 	// 1. HEALTHCHECK [OPTIONS] CMD command
 	// 2. HEALTHCHECK NONE
@@ -6,6 +6,17 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("InvalidDefaultArgInFrom", CheckMetadata{
+		Description: "Checks that the global ARGs used in FROM have valid defaults",
+		Rules: []RuleMetadata{
+			{Code: "InvalidDefaultArgInFrom", Severity: SeverityError, BuildKit: true, FileScoped: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkInvalidDefaultArgInFrom(node, ctx.BuildArgs)
+	}))
+}
+
 // checkInvalidDefaultArgInFrom validates that global ARG instructions used in
 // FROM statements have appropriate default values.
 //
@@ -22,7 +33,10 @@ import (
 //   - ARG TAG=latest used in FROM busybox:${TAG}
 //   - ARG VARIANT (without default) used in FROM busybox:stable${VARIANT} → results in busybox:stable
 //   - ARG TAG used with fallback: FROM alpine:${TAG:-3.14}
-func checkInvalidDefaultArgInFrom(ast *parser.Node) []Rule {
+//
+// ARGs given a value in buildArgs are not reported, since the build at hand
+// provides them.
+func checkInvalidDefaultArgInFrom(ast *parser.Node, buildArgs map[string]string) []Rule {
 	if ast == nil || len(ast.Children) == 0 {
 		return nil
 	}
@@ -38,7 +52,8 @@ func checkInvalidDefaultArgInFrom(ast *parser.Node) []Rule {
 		if strings.ToUpper(child.Value) == "ARG" {
 			argNames := extractArgNamesWithDefaults(child)
 			for argName, hasDefault := range argNames {
-				globalArgsWithDefaults[argName] = hasDefault
+				_, provided := buildArgs[argName]
+				globalArgsWithDefaults[argName] = hasDefault || provided
 			}
 		}
 	}
@@ -66,7 +81,7 @@ func checkInvalidDefaultArgInFrom(ast *parser.Node) []Rule {
 						Description: "ARG '" + varName + "' has no default value and is used in FROM instruction. Provide a default value or use parameter expansion with fallback: ${" + varName + ":-default}",
 						Url:         "https://docs.docker.com/reference/build-checks/invalid-default-arg-in-from/",
 						Severity:    SeverityError,
-					})
+					}.atVariable(varName))
 				}
 			}
 		}
@@ -85,7 +100,7 @@ func checkInvalidDefaultArgInFrom(ast *parser.Node) []Rule {
 						Description: "ARG '" + varName + "' has no default value and is used in FROM --platform flag. Provide a default value or use parameter expansion with fallback: ${" + varName + ":-default}",
 						Url:         "https://docs.docker.com/reference/build-checks/invalid-default-arg-in-from/",
 						Severity:    SeverityError,
-					})
+					}.atVariable(varName))
 				}
 			}
 		}
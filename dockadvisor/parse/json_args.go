@@ -6,6 +6,17 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("JSONArgsRecommended", CheckMetadata{
+		Description: "Checks that CMD and ENTRYPOINT use the exec form",
+		Rules: []RuleMetadata{
+			{Code: "JSONArgsRecommended", Severity: SeverityWarning, BuildKit: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkJSONArgsRecommended(node)
+	}))
+}
+
 // checkJSONArgsRecommended checks if CMD or ENTRYPOINT use shell form without an explicit SHELL instruction.
 // According to Docker best practices, using shell form prevents proper OS signal handling (SIGTERM, SIGKILL)
 // because the process runs as a child of /bin/sh.
@@ -59,7 +70,7 @@ func checkJSONArgsRecommended(ast *parser.Node) []Rule {
 				Description: "JSON arguments recommended for " + instruction + " to prevent unintended behavior related to OS signals",
 				Url:         "https://docs.docker.com/reference/build-checks/json-args-recommended/",
 				Severity:    SeverityWarning,
-			})
+			}.at(trimmedOriginal))
 		}
 	}
 
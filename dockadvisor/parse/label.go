@@ -6,16 +6,29 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("LABEL", CheckMetadata{
+		Description:  "Checks the format of LABEL instructions",
+		Instructions: []string{"LABEL"},
+		Rules: []RuleMetadata{
+			{Code: "LabelInvalidFormat", Severity: SeverityError},
+			{Code: "LabelMissingKeyValue", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseLABEL(node)
+	}))
+}
+
 func parseLABEL(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "LABEL requires at least one argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// Extract label configuration
 	labelConfig := extractLABELConfig(node)
 
-	// Validate that config is not empty
+	// Without a label there is no format to check
 	if strings.TrimSpace(labelConfig) == "" {
 		return []Rule{NewErrorRule(node, "LabelMissingKeyValue",
 			"LABEL instruction must specify at least one key=value pair",
@@ -0,0 +1,221 @@
+package parse
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// LintInput is a Dockerfile and the build it is linted for
+type LintInput struct {
+	// Filename is the path the Dockerfile is reported as, may be empty
+	Filename string
+
+	// Content is the source of the Dockerfile
+	Content string
+
+	// BuildArgs are the values passed with --build-arg. An ARG without a
+	// default that is given a value here isn't reported as missing one.
+	BuildArgs map[string]string
+
+	// Target is the stage being built, or empty to lint every stage.
+	// Findings in stages the target doesn't depend on are left out.
+	Target string
+
+	// Platform is the target platform, e.g. linux/arm64. It sets the
+	// TARGETPLATFORM, TARGETOS, TARGETARCH and TARGETVARIANT build args.
+	Platform string
+}
+
+// Options customizes a Lint run. The zero value runs every registered check
+// with the default configuration.
+type Options struct {
+	// Config customizes the rules reported, may be nil
+	Config *Config
+
+	// Checks are the checks to run, the registered checks when empty
+	Checks []Check
+
+	// Rules are the codes of the rules to report, every rule when empty
+	Rules []string
+}
+
+// LintResult is the result of Lint: the findings and score of the
+// Dockerfile and what they were computed for
+type LintResult struct {
+	*Result
+
+	// Filename is the path of the Dockerfile, as given in the input
+	Filename string `json:"file,omitempty"`
+
+	// Target is the stage the Dockerfile was linted for, empty when every
+	// stage was
+	Target string `json:"target,omitempty"`
+
+	// Stages lists the build stages in order
+	Stages []Stage `json:"stages"`
+}
+
+// Stage is a build stage, from its FROM instruction to the next one
+type Stage struct {
+	Index     int    `json:"index"`
+	Name      string `json:"name,omitempty"` // the AS name, empty when the stage is unnamed
+	Image     string `json:"image"`          // the image or stage it is based on
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+
+	// Used is set when the target depends on the stage, and for every
+	// stage when there is no target
+	Used bool `json:"used"`
+}
+
+// Lint lints a Dockerfile for the build described by input
+func Lint(ctx context.Context, input LintInput, opts Options) (*LintResult, error) {
+	linter := &Linter{Checks: opts.Checks, Config: opts.Config}
+	return linter.lint(ctx, input, opts.Rules)
+}
+
+// LintReader is like Lint, but reads the Dockerfile from r instead of
+// input.Content
+func LintReader(ctx context.Context, r io.Reader, input LintInput, opts Options) (*LintResult, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dockerfile: %w", err)
+	}
+	input.Content = string(content)
+	return Lint(ctx, input, opts)
+}
+
+// buildArgs returns the build args of the input with the target platform
+// args added
+func (input LintInput) buildArgs() (map[string]string, error) {
+	args := make(map[string]string, len(input.BuildArgs)+4)
+	for name, value := range input.BuildArgs {
+		args[name] = value
+	}
+
+	if input.Platform != "" {
+		parts := strings.Split(input.Platform, "/")
+		if len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+			return nil, fmt.Errorf("invalid platform %q, expected os/arch[/variant]", input.Platform)
+		}
+		args["TARGETPLATFORM"] = input.Platform
+		args["TARGETOS"] = parts[0]
+		args["TARGETARCH"] = parts[1]
+		if len(parts) == 3 {
+			args["TARGETVARIANT"] = parts[2]
+		}
+	}
+	return args, nil
+}
+
+// findStages returns the build stages of a Dockerfile with lineCount lines.
+// Without a target every stage is used; otherwise the target and the stages
+// it depends on through FROM, COPY --from and RUN --mount=from are.
+func findStages(ast *parser.Node, lineCount int, target string) ([]Stage, error) {
+	stages := []Stage{}
+	var dependencies [][]string
+	for _, child := range ast.Children {
+		switch strings.ToUpper(child.Value) {
+		case "FROM":
+			image, name, _ := extractFromComponents(child)
+			if len(stages) > 0 {
+				stages[len(stages)-1].EndLine = child.StartLine - 1
+			}
+			stages = append(stages, Stage{
+				Index:     len(stages),
+				Name:      name,
+				Image:     image,
+				StartLine: child.StartLine,
+				EndLine:   child.EndLine,
+			})
+			dependencies = append(dependencies, []string{image})
+		case "COPY":
+			if len(stages) == 0 {
+				continue
+			}
+			for _, flag := range child.Flags {
+				if from, ok := strings.CutPrefix(flag, "--from="); ok {
+					dependencies[len(stages)-1] = append(dependencies[len(stages)-1], from)
+				}
+			}
+		case "RUN":
+			if len(stages) == 0 {
+				continue
+			}
+			for _, flag := range child.Flags {
+				mount, ok := strings.CutPrefix(flag, "--mount=")
+				if !ok {
+					continue
+				}
+				for _, field := range strings.Split(mount, ",") {
+					if from, ok := strings.CutPrefix(field, "from="); ok {
+						dependencies[len(stages)-1] = append(dependencies[len(stages)-1], from)
+					}
+				}
+			}
+		}
+	}
+	if len(stages) > 0 {
+		stages[len(stages)-1].EndLine = max(stages[len(stages)-1].EndLine, lineCount)
+	}
+
+	// lookup returns the index of the stage a FROM image or --from value
+	// refers to, by name or by index, among the stages before stage
+	lookup := func(reference string, before int) int {
+		for i := range before {
+			if stages[i].Name != "" && strings.EqualFold(stages[i].Name, reference) {
+				return i
+			}
+		}
+		if index, err := strconv.Atoi(reference); err == nil && index >= 0 && index < before {
+			return index
+		}
+		return -1
+	}
+
+	if target == "" {
+		for i := range stages {
+			stages[i].Used = true
+		}
+		return stages, nil
+	}
+
+	targetIndex := lookup(target, len(stages))
+	if targetIndex < 0 {
+		return nil, fmt.Errorf("target stage %q could not be found", target)
+	}
+
+	queue := []int{targetIndex}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if stages[current].Used {
+			continue
+		}
+		stages[current].Used = true
+		for _, reference := range dependencies[current] {
+			if dependency := lookup(reference, current); dependency >= 0 {
+				queue = append(queue, dependency)
+			}
+		}
+	}
+	return stages, nil
+}
+
+// inUnusedStage reports whether a finding is on a line of a stage the target
+// doesn't depend on. Findings before the first stage or without a line are
+// in none.
+func inUnusedStage(rule Rule, stages []Stage) bool {
+	for _, stage := range stages {
+		if stage.StartLine <= rule.StartLine && rule.StartLine <= stage.EndLine {
+			return !stage.Used
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package parse
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const multiStageDockerfile = `ARG BASE
+FROM ${BASE}:latest AS Build
+WORKDIR app
+
+FROM alpine AS assets
+WORKDIR src
+
+FROM alpine AS release
+COPY --from=assets /src /src
+WORKDIR /app
+`
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         LintInput
+		opts          Options
+		expectedCodes []string
+		expectedUsed  []bool
+	}{
+		{
+			name:          "every stage",
+			input:         LintInput{Content: multiStageDockerfile},
+			expectedCodes: []string{"InvalidDefaultArgInFrom", "StageNameCasing", "WorkdirRelativePath", "WorkdirRelativePath"},
+			expectedUsed:  []bool{true, true, true},
+		},
+		{
+			name:          "build args",
+			input:         LintInput{Content: multiStageDockerfile, BuildArgs: map[string]string{"BASE": "golang"}},
+			expectedCodes: []string{"StageNameCasing", "WorkdirRelativePath", "WorkdirRelativePath"},
+			expectedUsed:  []bool{true, true, true},
+		},
+		{
+			name:          "target and its dependencies",
+			input:         LintInput{Content: multiStageDockerfile, Target: "release"},
+			expectedCodes: []string{"WorkdirRelativePath"},
+			expectedUsed:  []bool{false, true, true},
+		},
+		{
+			name:          "target is matched case-insensitively",
+			input:         LintInput{Content: multiStageDockerfile, Target: "build"},
+			expectedCodes: []string{"InvalidDefaultArgInFrom", "StageNameCasing", "WorkdirRelativePath"},
+			expectedUsed:  []bool{true, false, false},
+		},
+		{
+			name:          "only some rules",
+			input:         LintInput{Content: multiStageDockerfile},
+			opts:          Options{Rules: []string{"StageNameCasing"}},
+			expectedCodes: []string{"StageNameCasing"},
+			expectedUsed:  []bool{true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Lint(context.Background(), tt.input, tt.opts)
+			require.NoError(t, err)
+
+			codes := ruleCodes(result.Rules)
+			require.ElementsMatch(t, tt.expectedCodes, codes)
+
+			used := make([]bool, 0, len(result.Stages))
+			for _, stage := range result.Stages {
+				used = append(used, stage.Used)
+			}
+			require.Equal(t, tt.expectedUsed, used)
+
+			score, _ := (&Config{}).score(result.Rules)
+			require.Equal(t, score, result.Score, "the score should only count the findings reported")
+		})
+	}
+}
+
+func TestLintStages(t *testing.T) {
+	result, err := Lint(context.Background(), LintInput{Filename: "Dockerfile", Content: multiStageDockerfile, Target: "release"}, Options{})
+	require.NoError(t, err)
+	require.Equal(t, "Dockerfile", result.Filename)
+	require.Equal(t, "release", result.Target)
+	require.Equal(t, []Stage{
+		{Index: 0, Name: "Build", Image: "${BASE}:latest", StartLine: 2, EndLine: 4},
+		{Index: 1, Name: "assets", Image: "alpine", StartLine: 5, EndLine: 7, Used: true},
+		{Index: 2, Name: "release", Image: "alpine", StartLine: 8, EndLine: 11, Used: true},
+	}, result.Stages)
+}
+
+func TestLintErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    LintInput
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "unknown target",
+			input:    LintInput{Content: multiStageDockerfile, Target: "test"},
+			expected: `target stage "test" could not be found`,
+		},
+		{
+			name:     "invalid platform",
+			input:    LintInput{Content: multiStageDockerfile, Platform: "linux"},
+			expected: `invalid platform "linux", expected os/arch[/variant]`,
+		},
+		{
+			name:     "unknown rule",
+			input:    LintInput{Content: multiStageDockerfile},
+			opts:     Options{Rules: []string{"StageNameCase"}},
+			expected: `unknown rule "StageNameCase"`,
+		},
+		{
+			name:     "invalid config",
+			input:    LintInput{Content: multiStageDockerfile},
+			opts:     Options{Config: &Config{Preset: "lenient"}},
+			expected: `invalid config: unknown preset "lenient", expected one of: buildkit-parity, minimal, recommended, security, strict`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Lint(context.Background(), tt.input, tt.opts)
+			require.EqualError(t, err, tt.expected)
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Lint(ctx, LintInput{Content: multiStageDockerfile}, Options{})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLintReader(t *testing.T) {
+	result, err := LintReader(context.Background(), strings.NewReader(multiStageDockerfile), LintInput{Target: "release"}, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"WorkdirRelativePath"}, ruleCodes(result.Rules))
+
+	expected, err := ParseDockerfile(multiStageDockerfile)
+	require.NoError(t, err)
+	result, err = LintReader(context.Background(), strings.NewReader(multiStageDockerfile), LintInput{}, Options{})
+	require.NoError(t, err)
+	require.Equal(t, expected, result.Result)
+}
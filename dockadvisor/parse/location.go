@@ -0,0 +1,204 @@
+package parse
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// target describes the part of an instruction a rule points at. The parser
+// AST only carries line numbers, so checks record what they found and
+// locateRules resolves it to columns against the Dockerfile source.
+type target struct {
+	text     string // literal text, such as an argument or a flag
+	variable string // name of a variable referenced as $NAME or ${NAME...}
+	keyword  bool   // the instruction keyword
+}
+
+// at points the rule at the first occurrence of text as a whole word
+// within its instruction, ignoring the instruction keyword
+func (r Rule) at(text string) Rule {
+	r.target = target{text: text}
+	return r
+}
+
+// atVariable points the rule at the first reference to the named variable
+// within its instruction
+func (r Rule) atVariable(name string) Rule {
+	r.target = target{variable: name}
+	return r
+}
+
+// atKeyword points the rule at the instruction keyword
+func (r Rule) atKeyword() Rule {
+	r.target = target{keyword: true}
+	return r
+}
+
+// locateRules narrows rules with a target to the line and columns of that
+// target. Rules whose target can't be found keep their whole-line range.
+// It also resolves the fixes of the rules to edits.
+func locateRules(rules []Rule, dockerfileContent string) {
+	lines := strings.Split(dockerfileContent, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.target != (target{}) {
+			locateRule(rule, lines)
+			rule.target = target{}
+		}
+		if rule.fixes != nil {
+			rule.Fix = resolveFixes(rule.fixes, lines)
+			rule.fixes = nil
+		}
+	}
+}
+
+// locateRule resolves the target of a single rule
+func locateRule(rule *Rule, lines []string) {
+	if rule.StartLine < 1 || rule.StartLine > len(lines) {
+		return
+	}
+
+	endLine := rule.EndLine
+	if endLine < rule.StartLine || endLine > len(lines) {
+		endLine = min(max(rule.StartLine, endLine), len(lines))
+	}
+
+	for n := rule.StartLine; n <= endLine; n++ {
+		line := lines[n-1]
+		from := 0
+		if n == rule.StartLine {
+			// Skip the instruction keyword so that an argument equal to it
+			// (e.g. "RUN run") isn't matched there
+			kwStart, kwEnd := keywordSpan(line)
+			if rule.target.keyword {
+				if kwStart != kwEnd {
+					setColumns(rule, n, line, kwStart, kwEnd)
+				}
+				return
+			}
+			from = kwEnd
+		} else if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			// Comment lines inside a continuation aren't part of the instruction
+			continue
+		}
+
+		var start, end int
+		if rule.target.variable != "" {
+			start, end = findVariable(line, from, rule.target.variable)
+		} else {
+			start, end = findWord(line, from, rule.target.text)
+		}
+		if start >= 0 {
+			setColumns(rule, n, line, start, end)
+			return
+		}
+	}
+}
+
+// setColumns narrows the rule to the byte range [start, end) of line n
+func setColumns(rule *Rule, n int, line string, start, end int) {
+	rule.StartLine = n
+	rule.EndLine = n
+	rule.StartColumn = utf8.RuneCountInString(line[:start]) + 1
+	rule.EndColumn = utf8.RuneCountInString(line[:end]) + 1
+}
+
+// keywordSpan returns the byte range of the first word of line
+func keywordSpan(line string) (int, int) {
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	end := start
+	for end < len(line) && line[end] != ' ' && line[end] != '\t' {
+		end++
+	}
+	return start, end
+}
+
+// isWordBoundary reports whether b may delimit an argument: whitespace,
+// quotes, JSON array punctuation, or the = between a key and its value
+func isWordBoundary(b byte) bool {
+	return strings.IndexByte(" \t\"'[],=", b) >= 0
+}
+
+// findWord returns the byte range of the first occurrence of word in line
+// at or after from that is delimited by word boundaries, or -1, -1
+func findWord(line string, from int, word string) (int, int) {
+	if word == "" {
+		return -1, -1
+	}
+	for from <= len(line) {
+		idx := strings.Index(line[from:], word)
+		if idx < 0 {
+			return -1, -1
+		}
+		start := from + idx
+		end := start + len(word)
+		if (start == 0 || isWordBoundary(line[start-1])) && (end == len(line) || isWordBoundary(line[end])) {
+			return start, end
+		}
+		from = start + 1
+	}
+	return -1, -1
+}
+
+// findVariable returns the byte range of the first reference to the named
+// variable in line at or after from, either $NAME or ${NAME...}, or -1, -1
+func findVariable(line string, from int, name string) (int, int) {
+	for from < len(line) {
+		idx := strings.IndexByte(line[from:], '$')
+		if idx < 0 {
+			return -1, -1
+		}
+		start := from + idx
+		rest := line[start+1:]
+
+		if strings.HasPrefix(rest, "{"+name) {
+			after := rest[len(name)+1:]
+			if after != "" && (after[0] == '}' || after[0] == ':') {
+				if closing := strings.IndexByte(after, '}'); closing >= 0 {
+					return start, start + 1 + len(name) + 1 + closing + 1
+				}
+			}
+		} else if strings.HasPrefix(rest, name) {
+			end := start + 1 + len(name)
+			if end == len(line) || !isVariableChar(line[end]) {
+				return start, end
+			}
+		}
+		from = start + 1
+	}
+	return -1, -1
+}
+
+// isVariableChar reports whether b can be part of a variable name
+func isVariableChar(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+// warningRule converts a BuildKit parser warning to a rule, keeping the full
+// range of its location. BuildKit characters are 0-based; a location whose
+// characters are all zero covers whole lines.
+func warningRule(warning parser.Warning) Rule {
+	rule := Rule{
+		Code:        "ParserWarning",
+		Description: warning.Short,
+		Url:         warning.URL,
+		Severity:    SeverityWarning,
+	}
+
+	if location := warning.Location; location != nil {
+		rule.StartLine = location.Start.Line
+		rule.EndLine = location.End.Line
+		if location.Start.Character != 0 || location.End.Character != 0 {
+			rule.StartColumn = location.Start.Character + 1
+			rule.EndColumn = location.End.Character + 1
+		}
+	}
+
+	return rule
+}
@@ -0,0 +1,205 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRuleColumns checks that rules point at the argument, flag or variable
+// that triggered them
+func TestRuleColumns(t *testing.T) {
+	tests := []struct {
+		name              string
+		dockerfileContent string
+		code              string
+		expectedLine      int
+		expectedColumns   [2]int // start and end column
+	}{
+		{
+			name:              "EXPOSE points at the offending port",
+			dockerfileContent: "FROM alpine\nEXPOSE 80 443 80:80\n",
+			code:              "ExposeInvalidFormat",
+			expectedLine:      2,
+			expectedColumns:   [2]int{15, 20},
+		},
+		{
+			name:              "EXPOSE port on a continuation line",
+			dockerfileContent: "FROM alpine\nEXPOSE 80 \\\n    443/TCP\n",
+			code:              "ExposeProtoCasing",
+			expectedLine:      3,
+			expectedColumns:   [2]int{5, 12},
+		},
+		{
+			name:              "RUN points at the invalid flag",
+			dockerfileContent: "FROM alpine\nRUN --mount=type=cache,target=/root --network=bridge make\n",
+			code:              "RunInvalidNetworkFlag",
+			expectedLine:      2,
+			expectedColumns:   [2]int{37, 53},
+		},
+		{
+			name:              "FROM points at the platform flag",
+			dockerfileContent: "FROM --platform=$TARGETPLATFORM alpine\n",
+			code:              "RedundantTargetPlatform",
+			expectedLine:      1,
+			expectedColumns:   [2]int{6, 32},
+		},
+		{
+			name:              "stage name casing points at the stage name",
+			dockerfileContent: "FROM alpine AS Builder\n",
+			code:              "StageNameCasing",
+			expectedLine:      1,
+			expectedColumns:   [2]int{16, 23},
+		},
+		{
+			name:              "undefined variable with braces",
+			dockerfileContent: "FROM alpine\nCOPY app ${DEST}/bin/\n",
+			code:              "UndefinedVar",
+			expectedLine:      2,
+			expectedColumns:   [2]int{10, 17},
+		},
+		{
+			name:              "undefined variable is not matched as a prefix",
+			dockerfileContent: "FROM alpine\nARG DEST_DIR=/app\nCOPY app $DEST_DIR $DEST\n",
+			code:              "UndefinedVar",
+			expectedLine:      3,
+			expectedColumns:   [2]int{20, 25},
+		},
+		{
+			name:              "casing points at the keyword",
+			dockerfileContent: "FROM alpine\nRUN true\n  run false\nRUN true\n",
+			code:              "ConsistentInstructionCasing",
+			expectedLine:      3,
+			expectedColumns:   [2]int{3, 6},
+		},
+		{
+			name:              "unrecognized instruction points at the keyword",
+			dockerfileContent: "FROM alpine\nFOOBAR baz\n",
+			code:              "UnrecognizedInstruction",
+			expectedLine:      2,
+			expectedColumns:   [2]int{1, 7},
+		},
+		{
+			name:              "secret variable name",
+			dockerfileContent: "FROM alpine\nENV APP_NAME=demo API_TOKEN=abc\n",
+			code:              "SecretsUsedInArgOrEnv",
+			expectedLine:      2,
+			expectedColumns:   [2]int{19, 28},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDockerfile(tt.dockerfileContent)
+			require.NoError(t, err)
+
+			var found *Rule
+			for i := range result.Rules {
+				if result.Rules[i].Code == tt.code {
+					found = &result.Rules[i]
+					break
+				}
+			}
+			require.NotNil(t, found, "expected rule %s", tt.code)
+			require.Equal(t, tt.expectedLine, found.StartLine)
+			require.Equal(t, tt.expectedLine, found.EndLine)
+			require.Equal(t, tt.expectedColumns, [2]int{found.StartColumn, found.EndColumn})
+			require.Equal(t, target{}, found.target, "targets should be cleared once resolved")
+		})
+	}
+}
+
+func TestLocateRulesNotFound(t *testing.T) {
+	rules := []Rule{
+		{StartLine: 2, EndLine: 3, Code: "Missing", target: target{text: "nothere"}},
+		{StartLine: 9, EndLine: 9, Code: "OutOfRange", target: target{text: "x"}},
+	}
+
+	locateRules(rules, "FROM alpine\nRUN echo \\\n  hello\n")
+
+	require.Equal(t, 2, rules[0].StartLine)
+	require.Equal(t, 3, rules[0].EndLine)
+	require.Zero(t, rules[0].StartColumn)
+	require.Zero(t, rules[1].StartColumn)
+}
+
+func TestFindWord(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		from     int
+		word     string
+		expected [2]int
+	}{
+		{name: "whole word", line: "EXPOSE 80 8080", word: "8080", expected: [2]int{10, 14}},
+		{name: "not inside another word", line: "EXPOSE 8080 80", word: "80", expected: [2]int{12, 14}},
+		{name: "quoted", line: `CMD ["run", "app"]`, word: "app", expected: [2]int{13, 16}},
+		{name: "key before equals", line: "ENV KEY=value", word: "KEY", expected: [2]int{4, 7}},
+		{name: "skips before from", line: "RUN run", from: 3, word: "run", expected: [2]int{4, 7}},
+		{name: "missing", line: "RUN true", word: "false", expected: [2]int{-1, -1}},
+		{name: "empty word", line: "RUN true", word: "", expected: [2]int{-1, -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := findWord(tt.line, tt.from, tt.word)
+			require.Equal(t, tt.expected, [2]int{start, end})
+		})
+	}
+}
+
+func TestFindVariable(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		variable string
+		expected [2]int
+	}{
+		{name: "plain", line: "COPY $SRC /app", variable: "SRC", expected: [2]int{5, 9}},
+		{name: "braces", line: "COPY ${SRC} /app", variable: "SRC", expected: [2]int{5, 11}},
+		{name: "braces with default", line: "FROM alpine:${TAG:-3.20}", variable: "TAG", expected: [2]int{12, 24}},
+		{name: "not a prefix", line: "COPY $SRC_DIR $SRC", variable: "SRC", expected: [2]int{14, 18}},
+		{name: "not a braced prefix", line: "COPY ${SRC_DIR} ${SRC}", variable: "SRC", expected: [2]int{16, 22}},
+		{name: "missing", line: "COPY a b", variable: "SRC", expected: [2]int{-1, -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := findVariable(tt.line, 0, tt.variable)
+			require.Equal(t, tt.expected, [2]int{start, end})
+		})
+	}
+}
+
+func TestWarningRule(t *testing.T) {
+	t.Run("keeps the full location range", func(t *testing.T) {
+		rule := warningRule(parser.Warning{
+			Short: "short",
+			URL:   "https://example.com",
+			Location: &parser.Range{
+				Start: parser.Position{Line: 2, Character: 4},
+				End:   parser.Position{Line: 3, Character: 10},
+			},
+		})
+
+		require.Equal(t, "ParserWarning", rule.Code)
+		require.Equal(t, SeverityWarning, rule.Severity)
+		require.Equal(t, [4]int{2, 3, 5, 11}, [4]int{rule.StartLine, rule.EndLine, rule.StartColumn, rule.EndColumn})
+	})
+
+	t.Run("zero characters cover whole lines", func(t *testing.T) {
+		rule := warningRule(parser.Warning{
+			Short:    "short",
+			Location: &parser.Range{Start: parser.Position{Line: 2}, End: parser.Position{Line: 2}},
+		})
+
+		require.Equal(t, [4]int{2, 2, 0, 0}, [4]int{rule.StartLine, rule.EndLine, rule.StartColumn, rule.EndColumn})
+	})
+
+	t.Run("no location", func(t *testing.T) {
+		rule := warningRule(parser.Warning{Short: "short"})
+		require.Zero(t, rule.StartLine)
+		require.Zero(t, rule.StartColumn)
+	})
+}
@@ -6,28 +6,58 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("MAINTAINER", CheckMetadata{
+		Description:  "Checks MAINTAINER instructions",
+		Instructions: []string{"MAINTAINER"},
+		Rules: []RuleMetadata{
+			{Code: "MaintainerDeprecated", Severity: SeverityWarning, BuildKit: true},
+			{Code: "MaintainerMissingName", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseMAINTAINER(node)
+	}))
+}
+
 func parseMAINTAINER(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "MAINTAINER requires a name argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
+	// The deprecation doesn't depend on the name, so both are reported
+	var maintainerRules []Rule
 
 	// Validate that a name is provided
 	name := strings.TrimSpace(node.Next.Value)
 	if name == "" {
-		return []Rule{NewErrorRule(node, "MaintainerMissingName",
+		maintainerRules = append(maintainerRules, NewErrorRule(node, "MaintainerMissingName",
 			"MAINTAINER must specify a name",
-			"https://docs.docker.com/reference/dockerfile/#maintainer-deprecated")}
+			"https://docs.docker.com/reference/dockerfile/#maintainer-deprecated"))
 	}
 
-	// WARNING CHECKS - Accumulate warnings and return at end
-	var maintainerRules []Rule
-
 	// MAINTAINER is deprecated - warn users to use LABEL instead
 	maintainerRules = append(maintainerRules, NewWarningRule(node, "MaintainerDeprecated",
 		"MAINTAINER instruction is deprecated in favor of using label",
-		"https://docs.docker.com/reference/build-checks/maintainer-deprecated/"))
+		"https://docs.docker.com/reference/build-checks/maintainer-deprecated/").atKeyword().withFix(fixMaintainer(node)...))
 
 	return maintainerRules
 }
+
+// fixMaintainer returns the fix replacing a MAINTAINER instruction with the
+// equivalent LABEL, cased like its keyword. There is none for an
+// instruction spanning several lines or a name with quotes or escapes, nor
+// for a name with a $: LABEL expands variables where MAINTAINER doesn't.
+func fixMaintainer(node *parser.Node) []fix {
+	// The name is the rest of the line, as written
+	name := strings.TrimSpace(strings.TrimPrefix(node.Original, node.Value))
+	if node.StartLine != node.EndLine || strings.ContainsAny(name, "\"\\$") {
+		return nil
+	}
+
+	keyword := "LABEL"
+	if node.Value == strings.ToLower(node.Value) {
+		keyword = "label"
+	}
+	return []fix{replaceInstruction(node, keyword+` org.opencontainers.image.authors="`+name+`"`)}
+}
@@ -27,6 +27,12 @@ MAINTAINER John Doe <john@example.com>`,
 			expectedRules: []string{"MaintainerDeprecated"},
 		},
 		// Invalid MAINTAINER instructions
+		{
+			name: "empty name",
+			dockerfile: `FROM alpine
+MAINTAINER [""]`,
+			expectedRules: []string{"MaintainerMissingName", "MaintainerDeprecated"},
+		},
 		{
 			name: "no arguments",
 			dockerfile: `FROM alpine
@@ -6,6 +6,17 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("MultipleInstructionsDisallowed", CheckMetadata{
+		Description: "Checks that a stage has at most one CMD, ENTRYPOINT and HEALTHCHECK",
+		Rules: []RuleMetadata{
+			{Code: "MultipleInstructionsDisallowed", Severity: SeverityError, BuildKit: true, FileScoped: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkMultipleInstructionsDisallowed(node)
+	}))
+}
+
 // checkMultipleInstructionsDisallowed validates that certain instructions
 // (CMD, HEALTHCHECK, ENTRYPOINT) appear at most once per stage.
 //
@@ -68,7 +79,7 @@ func checkMultipleInstructionsDisallowed(ast *parser.Node) []Rule {
 					Description: "Multiple " + instruction + " instructions should not be used in the same stage; only the last one takes effect",
 					Url:         "https://docs.docker.com/reference/build-checks/multiple-instructions-disallowed/",
 					Severity:    SeverityError,
-				})
+				}.atKeyword())
 			} else {
 				// First occurrence
 				stageInstructions[instruction] = &instructionInfo{
@@ -6,12 +6,24 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("ONBUILD", CheckMetadata{
+		Description:  "Checks the trigger of ONBUILD instructions",
+		Instructions: []string{"ONBUILD"},
+		Rules: []RuleMetadata{
+			{Code: "OnbuildMissingInstruction", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseONBUILD(node)
+	}))
+}
+
 func parseONBUILD(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "ONBUILD requires an instruction argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// ONBUILD must be followed by another Dockerfile instruction
 	// Extract the instruction from the Original field
 	config := strings.TrimPrefix(node.Original, node.Value)
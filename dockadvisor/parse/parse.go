@@ -1,13 +1,22 @@
 package parse
 
 import (
-	"bytes"
-	"fmt"
 	"strings"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("ParserWarning", CheckMetadata{
+		Description: "Reports the warnings of the BuildKit parser",
+		Rules: []RuleMetadata{
+			{Code: "ParserWarning", Severity: SeverityWarning},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parserWarningRules(ctx.Warnings)
+	}))
+}
+
 const invalidInstructionCode = "InvalidInstruction"
 
 // Severity represents the severity level of a rule violation
@@ -17,20 +26,44 @@ const (
 	SeverityFatal   Severity = "fatal"
 	SeverityError   Severity = "error"
 	SeverityWarning Severity = "warning"
+
+	// Info and hint rules are advisory suggestions that don't cost points
+	SeverityInfo Severity = "info"
+	SeverityHint Severity = "hint"
 )
 
 type Result struct {
 	Rules []Rule `json:"rules"`
 	Score int    `json:"score"`
+
+	// Breakdown lists the points lost to each rule code, the most
+	// expensive first
+	Breakdown []ScoreItem `json:"breakdown"`
+
+	// Suppressed holds the rules silenced by "# dockadvisor ignore=..."
+	// comments. They are not part of the score.
+	Suppressed []Rule `json:"suppressed,omitempty"`
+
+	config *Config // the configuration the result was scored with
 }
 
 type Rule struct {
-	StartLine   int      // the line in the original dockerfile where the rule starts
-	EndLine     int      // the line in the original dockerfile where the rule ends
+	StartLine   int      `json:"startLine"`   // the line in the original dockerfile where the rule starts
+	EndLine     int      `json:"endLine"`     // the line in the original dockerfile where the rule ends
+	StartColumn int      `json:"startColumn"` // the 1-based column where the rule starts, 0 when it covers whole lines
+	EndColumn   int      `json:"endColumn"`   // the column just past the end of the rule, 0 when it covers whole lines
 	Code        string   `json:"code"`
 	Description string   `json:"description"`
 	Url         string   `json:"url"`
 	Severity    Severity `json:"severity"`
+	Fingerprint string   `json:"fingerprint"` // identifies the finding across edits that move it to another line
+
+	// Fix holds the edits that resolve the finding, to be applied together,
+	// or nothing when it can't be fixed automatically. See ApplyFixes.
+	Fix []Edit `json:"fix,omitempty"`
+
+	target target // what the rule points at, resolved to columns by locateRules
+	fixes  []fix  // the fixes checks requested, resolved to Fix by locateRules
 }
 
 // NewErrorRule creates a new Rule with error severity
@@ -69,178 +102,33 @@ func NewFatalRule(node *parser.Node, code, description, url string) Rule {
 	}
 }
 
+// ParseDockerfile lints a Dockerfile with the default configuration
 func ParseDockerfile(dockerfileContent string) (*Result, error) {
-	dockerfile := bytes.NewBufferString(dockerfileContent)
-	result, err := parser.Parse(dockerfile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse dockerfile: %v", err)
-	}
+	return ParseDockerfileWithConfig(dockerfileContent, nil)
+}
 
-	var parseRules []Rule
+// ParseDockerfileWithConfig lints a Dockerfile, leaving out the rules the
+// configuration disables and applying its severity overrides and rule
+// options. A nil config behaves like ParseDockerfile.
+func ParseDockerfileWithConfig(dockerfileContent string, config *Config) (*Result, error) {
+	linter := &Linter{Config: config}
+	return linter.Lint(dockerfileContent)
+}
 
-	// Convert parser warnings to rules
-	for _, warning := range result.Warnings {
+// parserWarningRules converts the parser warnings to rules
+func parserWarningRules(warnings []parser.Warning) []Rule {
+	var rules []Rule
+	for _, warning := range warnings {
 		// Skip empty continuation warnings since we have a dedicated check for those
 		if strings.Contains(warning.URL, "no-empty-continuation") {
 			continue
 		}
 
-		startLine := 0
-		endLine := 0
-		if warning.Location != nil {
-			startLine = warning.Location.Start.Line
-			endLine = warning.Location.End.Line
-		}
-
-		parseRules = append(parseRules, Rule{
-			StartLine:   startLine,
-			EndLine:     endLine,
-			Code:        "ParserWarning",
-			Description: warning.Short,
-			Url:         warning.URL,
-			Severity:    SeverityWarning,
-		})
-	}
-
-	// Check for empty continuation lines (applies to all instructions)
-	continuationRules := checkEmptyContinuations(dockerfileContent)
-	if len(continuationRules) != 0 {
-		parseRules = append(parseRules, continuationRules...)
-	}
-
-	// Check for consistent instruction casing (applies to all instructions)
-	casingRules := checkConsistentInstructionCasing(result.AST)
-	if len(casingRules) != 0 {
-		parseRules = append(parseRules, casingRules...)
-	}
-
-	// Check for duplicate stage names (applies to all FROM instructions)
-	duplicateStageRules := checkDuplicateStageNames(result.AST)
-	if len(duplicateStageRules) != 0 {
-		parseRules = append(parseRules, duplicateStageRules...)
-	}
-
-	// Check for constant platform flags in FROM instructions (global check)
-	platformConstRules := checkPlatformFlagConstDisallowed(result.AST)
-	if len(platformConstRules) != 0 {
-		parseRules = append(parseRules, platformConstRules...)
-	}
-
-	// Check for JSON args recommendation in CMD/ENTRYPOINT (global check)
-	jsonArgsRules := checkJSONArgsRecommended(result.AST)
-	if len(jsonArgsRules) != 0 {
-		parseRules = append(parseRules, jsonArgsRules...)
+		rules = append(rules, warningRule(warning))
 	}
-
-	// Check for undefined ARG references in FROM instructions
-	undefinedArgRules := checkUndefinedArgInFrom(result.AST)
-	if len(undefinedArgRules) != 0 {
-		parseRules = append(parseRules, undefinedArgRules...)
-	}
-
-	// Check for undefined variables across all instructions
-	undefinedVarRules := checkUndefinedVar(result.AST)
-	if len(undefinedVarRules) != 0 {
-		parseRules = append(parseRules, undefinedVarRules...)
-	}
-
-	// Check for multiple disallowed instructions (CMD, HEALTHCHECK, ENTRYPOINT)
-	multipleInstructionsRules := checkMultipleInstructionsDisallowed(result.AST)
-	if len(multipleInstructionsRules) != 0 {
-		parseRules = append(parseRules, multipleInstructionsRules...)
-	}
-
-	// Check for secrets in ARG or ENV instructions
-	secretsRules := checkSecretsInArgOrEnv(result.AST)
-	if len(secretsRules) != 0 {
-		parseRules = append(parseRules, secretsRules...)
-	}
-
-	// Check for invalid default ARG values in FROM instructions
-	invalidDefaultArgRules := checkInvalidDefaultArgInFrom(result.AST)
-	if len(invalidDefaultArgRules) != 0 {
-		parseRules = append(parseRules, invalidDefaultArgRules...)
-	}
-
-	for _, child := range result.AST.Children {
-		instruction := child.Value
-		insUppercase := strings.ToUpper(instruction)
-		var insRules []Rule
-		switch {
-		case insUppercase == "FROM":
-			insRules = parseFROM(child)
-		case insUppercase == "WORKDIR":
-			insRules = parseWorkdir(child)
-		case insUppercase == "RUN":
-			insRules = parseRun(child)
-		case insUppercase == "EXPOSE":
-			insRules = parseEXPOSE(child)
-		case insUppercase == "CMD":
-			insRules = parseCMD(child)
-		case insUppercase == "ENTRYPOINT":
-			insRules = parseENTRYPOINT(child)
-		case insUppercase == "SHELL":
-			insRules = parseSHELL(child)
-		case insUppercase == "VOLUME":
-			insRules = parseVOLUME(child)
-		case insUppercase == "USER":
-			insRules = parseUSER(child)
-		case insUppercase == "LABEL":
-			insRules = parseLABEL(child)
-		case insUppercase == "ENV":
-			insRules = parseENV(child)
-		case insUppercase == "ARG":
-			insRules = parseARG(child)
-		case insUppercase == "COPY":
-			insRules = parseCOPY(child)
-		case insUppercase == "ADD":
-			insRules = parseADD(child)
-		case insUppercase == "HEALTHCHECK":
-			insRules = parseHEALTHCHECK(child)
-		case insUppercase == "ONBUILD":
-			insRules = parseONBUILD(child)
-		case insUppercase == "STOPSIGNAL":
-			insRules = parseSTOPSIGNAL(child)
-		case insUppercase == "MAINTAINER":
-			insRules = parseMAINTAINER(child)
-		default:
-			// Unrecognized instruction
-			insRules = []Rule{NewFatalRule(child, "UnrecognizedInstruction",
-				fmt.Sprintf("'%s' is not a recognized Dockerfile instruction", instruction),
-				"https://docs.docker.com/reference/dockerfile/")}
-		}
-
-		if len(insRules) != 0 {
-			parseRules = append(parseRules, insRules...)
-		}
-	}
-
-	score := calculateScore(parseRules)
-	return &Result{Rules: parseRules, Score: score}, nil
+	return rules
 }
 
 func invalidInstructionRule(node *parser.Node, description string) Rule {
-	return NewErrorRule(node, invalidInstructionCode, description, "")
-}
-
-// calculateScore calculates the Dockerfile score based on rule violations
-// Score = 100 - (errors × 15 + warnings × 5), minimum 0
-// If any fatal rule is found, score is 0
-func calculateScore(rules []Rule) int {
-	var errorCount, warningCount int
-	for _, rule := range rules {
-		if rule.Severity == SeverityFatal {
-			return 0
-		} else if rule.Severity == SeverityError {
-			errorCount++
-		} else if rule.Severity == SeverityWarning {
-			warningCount++
-		}
-	}
-
-	score := 100 - (errorCount*15 + warningCount*5)
-	if score < 0 {
-		return 0
-	}
-	return score
+	return NewErrorRule(node, invalidInstructionCode, description, "").atKeyword()
 }
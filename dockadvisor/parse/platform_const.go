@@ -6,6 +6,17 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("FromPlatformFlagConstDisallowed", CheckMetadata{
+		Description: "Checks that FROM --platform isn't a constant",
+		Rules: []RuleMetadata{
+			{Code: "FromPlatformFlagConstDisallowed", Severity: SeverityWarning, BuildKit: true, FileScoped: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkPlatformFlagConstDisallowed(node)
+	}))
+}
+
 // checkPlatformFlagConstDisallowed checks if FROM instructions use constant platform values inappropriately.
 // Constant platform values (e.g., "linux/amd64") are allowed when:
 // 1. The FROM instruction has a stage name (AS <name>)
@@ -102,7 +113,7 @@ func checkPlatformFlagConstDisallowed(ast *parser.Node) []Rule {
 			Description: "FROM --platform should not use a constant value '" + platformFlag + "'. Use a variable like $BUILDPLATFORM or $TARGETPLATFORM, or specify --platform at build time instead.",
 			Url:         "https://docs.docker.com/reference/build-checks/from-platform-flag-const-disallowed/",
 			Severity:    SeverityWarning,
-		})
+		}.at("--platform="+platformFlag))
 	}
 
 	return rules
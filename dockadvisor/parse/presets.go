@@ -0,0 +1,190 @@
+package parse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// securityRules are the rules about secrets, privileges and the supply chain.
+// Rules that only validate the syntax of a security-related flag or
+// instruction, such as RunInvalidSecurityFlag, are not among them: they break
+// the build rather than weaken it.
+var securityRules = map[string]bool{
+	// Secrets
+	"SecretsUsedInArgOrEnv": true,
+	// Privileges
+	"RunSecurityInsecure": true,
+	"UserRoot":            true,
+	// Supply chain: what is built on must be what was intended
+	"FromUnpinnedImage":        true,
+	"AddRemoteWithoutChecksum": true,
+	"InvalidDefaultArgInFrom":  true,
+	"UndefinedArgInFrom":       true,
+}
+
+// presets maps each built-in preset to the severity it gives a rule, or ""
+// to turn the rule off
+var presets = map[string]func(rule RuleMetadata) Severity{
+	// Only the rules that find build failures
+	"minimal": func(rule RuleMetadata) Severity {
+		if rule.Severity != SeverityFatal && rule.Severity != SeverityError {
+			return ""
+		}
+		return rule.Severity
+	},
+	// Every rule with its default severity
+	"recommended": func(rule RuleMetadata) Severity {
+		if rule.OptIn {
+			return ""
+		}
+		return rule.Severity
+	},
+	// Every rule, warnings become errors
+	"strict": func(rule RuleMetadata) Severity {
+		if rule.OptIn {
+			return ""
+		}
+		if rule.Severity == SeverityWarning {
+			return SeverityError
+		}
+		return rule.Severity
+	},
+	// Only the security rules, including the opt-in ones
+	"security": func(rule RuleMetadata) Severity {
+		if !securityRules[rule.Code] {
+			return ""
+		}
+		return rule.Severity
+	},
+	// Only the BuildKit build checks, reported as warnings like BuildKit does
+	"buildkit-parity": func(rule RuleMetadata) Severity {
+		if !rule.BuildKit {
+			return ""
+		}
+		return SeverityWarning
+	},
+}
+
+// Presets returns the names of the built-in presets
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Preset returns the configuration of a built-in preset. It lists the code
+// of every registered rule, either enabled with the severity the preset
+// reports it with, or disabled.
+func Preset(name string) (*Config, error) {
+	return preset(name, Rules())
+}
+
+// preset returns the configuration of a built-in preset for the given rules
+func preset(name string, rules []RuleMetadata) (*Config, error) {
+	severityFor, ok := presets[name]
+	if !ok {
+		return nil, unknownPresetError(name)
+	}
+
+	config := &Config{Rules: make(map[string]RuleConfig, len(rules))}
+	for _, rule := range rules {
+		severity := severityFor(rule)
+		enabled := severity != ""
+		config.Rules[rule.Code] = RuleConfig{Enabled: &enabled, Severity: severity}
+	}
+	return config, nil
+}
+
+func unknownPresetError(name string) error {
+	return fmt.Errorf("unknown preset %q, expected one of: %s", name, strings.Join(Presets(), ", "))
+}
+
+// Merge returns r with the settings set in override replaced
+func (r RuleConfig) Merge(override RuleConfig) RuleConfig {
+	if override.Enabled != nil {
+		r.Enabled = override.Enabled
+	}
+	if override.Severity != "" {
+		r.Severity = override.Severity
+	}
+	if override.Options != nil {
+		r.Options = override.Options
+	}
+	if override.Weight != nil {
+		r.Weight = override.Weight
+	}
+	if override.Cap != nil {
+		r.Cap = override.Cap
+	}
+	return r
+}
+
+// withPreset returns the configuration with its preset expanded for the
+// given rules: the preset's rules with the configured rule settings applied
+// on top
+func (c *Config) withPreset(rules []RuleMetadata) *Config {
+	if c == nil || c.Preset == "" {
+		return c
+	}
+
+	expanded, err := preset(c.Preset, rules)
+	if err != nil {
+		// Validate rejects unknown presets
+		return c
+	}
+
+	for code, ruleConfig := range c.Rules {
+		expanded.Rules[code] = expanded.Rules[code].Merge(ruleConfig)
+	}
+	expanded.Scoring = c.Scoring
+	return expanded
+}
+
+// withOptIn returns the configuration with the opt-in rules it doesn't
+// enable turned off
+func (c *Config) withOptIn(rules []RuleMetadata) *Config {
+	resolved := c
+	for _, rule := range rules {
+		if !rule.OptIn {
+			continue
+		}
+		ruleConfig := resolved.rule(rule.Code)
+		if ruleConfig.Enabled != nil {
+			continue
+		}
+		if resolved == c {
+			resolved = c.clone()
+		}
+		disabled := false
+		ruleConfig.Enabled = &disabled
+		resolved.Rules[rule.Code] = ruleConfig
+	}
+	return resolved
+}
+
+// rule returns the settings of a rule, which are empty when the
+// configuration is nil or doesn't set any
+func (c *Config) rule(code string) RuleConfig {
+	if c == nil {
+		return RuleConfig{}
+	}
+	return c.Rules[code]
+}
+
+// clone returns a copy of the configuration whose rule settings can be
+// changed without changing c
+func (c *Config) clone() *Config {
+	clone := &Config{Rules: map[string]RuleConfig{}}
+	if c != nil {
+		clone.Preset = c.Preset
+		clone.Scoring = c.Scoring
+		for code, ruleConfig := range c.Rules {
+			clone.Rules[code] = ruleConfig
+		}
+	}
+	return clone
+}
@@ -0,0 +1,164 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresets(t *testing.T) {
+	dockerfile := `FROM alpine:3.20
+MAINTAINER someone
+ARG DB_PASSWORD
+WORKDIR app
+RUN --network=bridge echo hello
+RUN --security=insecure make
+USER root
+`
+
+	enabled := true
+
+	tests := []struct {
+		name     string
+		config   *Config
+		expected map[string]Severity
+	}{
+		{
+			name:   "no preset",
+			config: nil,
+			expected: map[string]Severity{
+				"MaintainerDeprecated":  SeverityWarning,
+				"SecretsUsedInArgOrEnv": SeverityWarning,
+				"WorkdirRelativePath":   SeverityWarning,
+				"RunInvalidNetworkFlag": SeverityError,
+			},
+		},
+		{
+			name:   "minimal",
+			config: &Config{Preset: "minimal"},
+			expected: map[string]Severity{
+				"RunInvalidNetworkFlag": SeverityError,
+			},
+		},
+		{
+			name:   "recommended",
+			config: &Config{Preset: "recommended"},
+			expected: map[string]Severity{
+				"MaintainerDeprecated":  SeverityWarning,
+				"SecretsUsedInArgOrEnv": SeverityWarning,
+				"WorkdirRelativePath":   SeverityWarning,
+				"RunInvalidNetworkFlag": SeverityError,
+			},
+		},
+		{
+			name:   "strict",
+			config: &Config{Preset: "strict"},
+			expected: map[string]Severity{
+				"MaintainerDeprecated":  SeverityError,
+				"SecretsUsedInArgOrEnv": SeverityError,
+				"WorkdirRelativePath":   SeverityError,
+				"RunInvalidNetworkFlag": SeverityError,
+			},
+		},
+		{
+			name:   "security",
+			config: &Config{Preset: "security"},
+			expected: map[string]Severity{
+				"SecretsUsedInArgOrEnv": SeverityWarning,
+				"RunSecurityInsecure":   SeverityWarning,
+				"UserRoot":              SeverityWarning,
+				"FromUnpinnedImage":     SeverityWarning,
+			},
+		},
+		{
+			name:   "buildkit-parity",
+			config: &Config{Preset: "buildkit-parity"},
+			expected: map[string]Severity{
+				"MaintainerDeprecated":  SeverityWarning,
+				"SecretsUsedInArgOrEnv": SeverityWarning,
+				"WorkdirRelativePath":   SeverityWarning,
+			},
+		},
+		{
+			name: "rule settings apply on top of the preset",
+			config: &Config{Preset: "security", Rules: map[string]RuleConfig{
+				"WorkdirRelativePath":   {Enabled: &enabled},
+				"SecretsUsedInArgOrEnv": {Severity: SeverityError},
+				"RunInvalidNetworkFlag": {Options: map[string][]string{"networks": {"bridge"}}},
+			}},
+			expected: map[string]Severity{
+				"SecretsUsedInArgOrEnv": SeverityError,
+				"WorkdirRelativePath":   SeverityWarning,
+				"RunSecurityInsecure":   SeverityWarning,
+				"UserRoot":              SeverityWarning,
+				"FromUnpinnedImage":     SeverityWarning,
+			},
+		},
+		{
+			name: "opt-in rule enabled without a preset",
+			config: &Config{Rules: map[string]RuleConfig{
+				"UserRoot": {Enabled: &enabled},
+			}},
+			expected: map[string]Severity{
+				"MaintainerDeprecated":  SeverityWarning,
+				"SecretsUsedInArgOrEnv": SeverityWarning,
+				"WorkdirRelativePath":   SeverityWarning,
+				"RunInvalidNetworkFlag": SeverityError,
+				"UserRoot":              SeverityWarning,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDockerfileWithConfig(dockerfile, tt.config)
+			require.NoError(t, err)
+
+			severities := map[string]Severity{}
+			for _, rule := range result.Rules {
+				severities[rule.Code] = rule.Severity
+			}
+			require.Equal(t, tt.expected, severities)
+		})
+	}
+}
+
+// TestSecurityPreset checks that the security preset holds the rules about
+// secrets, privileges and the supply chain, and not the validators of
+// security-related syntax
+func TestSecurityPreset(t *testing.T) {
+	config, err := Preset("security")
+	require.NoError(t, err)
+
+	enabled := []string{}
+	for code, ruleConfig := range config.Rules {
+		if *ruleConfig.Enabled {
+			enabled = append(enabled, code)
+		}
+	}
+	require.ElementsMatch(t, []string{
+		"SecretsUsedInArgOrEnv",
+		"RunSecurityInsecure",
+		"UserRoot",
+		"FromUnpinnedImage",
+		"AddRemoteWithoutChecksum",
+		"InvalidDefaultArgInFrom",
+		"UndefinedArgInFrom",
+	}, enabled)
+
+	for _, code := range []string{"RunInvalidSecurityFlag", "RunInvalidMountFlag", "UserMissingValue", "UserInvalidFormat", "FromInvalidImageReference"} {
+		require.False(t, *config.Rules[code].Enabled, "%s only validates syntax", code)
+	}
+}
+
+func TestPreset(t *testing.T) {
+	for _, name := range Presets() {
+		config, err := Preset(name)
+		require.NoError(t, err)
+		require.Len(t, config.Rules, len(Rules()), "a preset lists every rule")
+		require.NoError(t, config.Validate())
+	}
+
+	_, err := Preset("lenient")
+	require.EqualError(t, err, `unknown preset "lenient", expected one of: buildkit-parity, minimal, recommended, security, strict`)
+}
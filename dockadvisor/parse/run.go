@@ -2,17 +2,36 @@ package parse
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
-func parseRun(node *parser.Node) []Rule {
+func init() {
+	Register(NewCheck("RUN", CheckMetadata{
+		Description:  "Checks the command and flags of RUN instructions",
+		Instructions: []string{"RUN"},
+		Rules: []RuleMetadata{
+			{Code: "RunInvalidExecForm", Severity: SeverityError},
+			{Code: "RunInvalidMountFlag", Severity: SeverityError},
+			// networks: networks allowed in addition to default, none and host
+			{Code: "RunInvalidNetworkFlag", Severity: SeverityError, Options: []string{"networks"}},
+			{Code: "RunInvalidSecurityFlag", Severity: SeverityError},
+			{Code: "RunSecurityInsecure", Severity: SeverityWarning, OptIn: true},
+			{Code: "RunMissingCommand", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseRun(node, ctx.Config)
+	}))
+}
+
+func parseRun(node *parser.Node, config *Config) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "RUN requires at least one argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// Extract command and validate flags
 	command := extractRunCommand(node)
 
@@ -23,12 +42,16 @@ func parseRun(node *parser.Node) []Rule {
 			"https://docs.docker.com/reference/dockerfile/#run")}
 	}
 
+	// The command and every flag are checked independently so that every
+	// problem is reported at once
+	var runRules []Rule
+
 	// Check if it's exec form (starts with '[')
 	if strings.HasPrefix(strings.TrimSpace(command), "[") {
 		if !checkExecFormJSON(command) {
-			return []Rule{NewErrorRule(node, "RunInvalidExecForm",
+			runRules = append(runRules, NewErrorRule(node, "RunInvalidExecForm",
 				"RUN exec form must be a valid JSON array with double quotes",
-				"https://docs.docker.com/reference/dockerfile/#run")}
+				"https://docs.docker.com/reference/dockerfile/#run"))
 		}
 	}
 
@@ -38,19 +61,20 @@ func parseRun(node *parser.Node) []Rule {
 		if strings.HasPrefix(flag, "--mount=") {
 			mountValue := strings.TrimPrefix(flag, "--mount=")
 			if !checkMountFlag(mountValue) {
-				return []Rule{NewErrorRule(node, "RunInvalidMountFlag",
+				runRules = append(runRules, NewErrorRule(node, "RunInvalidMountFlag",
 					"RUN --mount flag has invalid format: '"+flag+"'",
-					"https://docs.docker.com/reference/dockerfile/#run---mount")}
+					"https://docs.docker.com/reference/dockerfile/#run---mount").at(flag))
 			}
 		}
 
 		// Validate --network flag
 		if strings.HasPrefix(flag, "--network=") {
 			networkValue := strings.TrimPrefix(flag, "--network=")
-			if !checkNetworkFlag(networkValue) {
-				return []Rule{NewErrorRule(node, "RunInvalidNetworkFlag",
-					"RUN --network flag must be one of: default, none, host. Got: '"+networkValue+"'",
-					"https://docs.docker.com/reference/dockerfile/#run---network")}
+			allowedNetworks := config.option("RunInvalidNetworkFlag", "networks")
+			if !checkNetworkFlag(networkValue) && !slices.Contains(allowedNetworks, networkValue) {
+				runRules = append(runRules, NewErrorRule(node, "RunInvalidNetworkFlag",
+					"RUN --network flag must be one of: "+strings.Join(append([]string{"default", "none", "host"}, allowedNetworks...), ", ")+". Got: '"+networkValue+"'",
+					"https://docs.docker.com/reference/dockerfile/#run---network").at(flag))
 			}
 		}
 
@@ -58,15 +82,18 @@ func parseRun(node *parser.Node) []Rule {
 		if strings.HasPrefix(flag, "--security=") {
 			securityValue := strings.TrimPrefix(flag, "--security=")
 			if !checkSecurityFlag(securityValue) {
-				return []Rule{NewErrorRule(node, "RunInvalidSecurityFlag",
+				runRules = append(runRules, NewErrorRule(node, "RunInvalidSecurityFlag",
 					"RUN --security flag must be one of: sandbox, insecure. Got: '"+securityValue+"'",
-					"https://docs.docker.com/reference/dockerfile/#run---security")}
+					"https://docs.docker.com/reference/dockerfile/#run---security").at(flag))
+			} else if securityValue == "insecure" {
+				runRules = append(runRules, NewWarningRule(node, "RunSecurityInsecure",
+					"RUN --security=insecure runs the command outside of the sandbox with elevated privileges",
+					"https://docs.docker.com/reference/dockerfile/#run---security").at(flag))
 			}
 		}
 	}
 
-	// No warnings in this file
-	return nil
+	return runRules
 }
 
 // extractRunCommand extracts the command string from the RUN instruction
@@ -281,6 +281,7 @@ func TestParseRUN(t *testing.T) {
 			expectedRules:     []string{},
 		},
 		{
+			// RunSecurityInsecure is opt-in, see TestRunSecurityInsecure
 			name:              "with --security=insecure",
 			dockerfileContent: `RUN --security=insecure some-privileged-command`,
 			expectedRules:     []string{},
@@ -329,6 +330,16 @@ EOF`,
 			dockerfileContent: `RUN --mount=type=invalid,target=/tmp echo hello`,
 			expectedRules:     []string{"RunInvalidMountFlag"},
 		},
+		{
+			name:              "every invalid flag is reported",
+			dockerfileContent: `RUN --mount=type=invalid,target=/tmp --network=invalid --security=invalid echo hello`,
+			expectedRules:     []string{"RunInvalidMountFlag", "RunInvalidNetworkFlag", "RunInvalidSecurityFlag"},
+		},
+		{
+			name:              "invalid flag and invalid exec form",
+			dockerfileContent: `RUN --network=invalid ['echo', 'hello']`,
+			expectedRules:     []string{"RunInvalidExecForm", "RunInvalidNetworkFlag"},
+		},
 		// Edge cases
 		{
 			name:              "exec form with escaped backslashes",
@@ -372,3 +383,19 @@ EOF`,
 		})
 	}
 }
+
+func TestRunSecurityInsecure(t *testing.T) {
+	dockerfile := "FROM alpine\nRUN --security=insecure some-privileged-command\n"
+
+	result, err := ParseDockerfile(dockerfile)
+	require.NoError(t, err)
+	require.Empty(t, result.Rules, "the rule is off by default")
+
+	enabled := true
+	result, err = ParseDockerfileWithConfig(dockerfile, &Config{Rules: map[string]RuleConfig{
+		"RunSecurityInsecure": {Enabled: &enabled},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"RunSecurityInsecure"}, getRuleCodes(result.Rules))
+	require.Equal(t, 5, result.Rules[0].StartColumn)
+}
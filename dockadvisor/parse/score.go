@@ -0,0 +1,127 @@
+package parse
+
+import (
+	"sort"
+)
+
+// defaultWeights are the points a finding of each severity costs. A fatal
+// finding costs every point, so by default it drops the score to 0. Info and
+// hint findings are left out of the score entirely.
+var defaultWeights = map[Severity]int{
+	SeverityFatal:   100,
+	SeverityError:   15,
+	SeverityWarning: 5,
+}
+
+// Scoring customizes how findings cost points. The score is 100 minus the
+// points lost to every rule code, and never goes below 0.
+type Scoring struct {
+	// Weights overrides the points a finding of each severity costs.
+	// Lowering the fatal weight makes fatal findings cost that many points
+	// instead of dropping the score to 0.
+	Weights map[Severity]int `json:"weights,omitempty" yaml:"weights"`
+
+	// Cap limits the points lost to all findings of a single rule code,
+	// 0 means no limit. It doesn't apply to fatal findings.
+	Cap int `json:"cap,omitempty" yaml:"cap"`
+}
+
+// ScoreItem is the number of points lost to the findings of one rule code
+type ScoreItem struct {
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Count    int      `json:"count"`  // number of findings
+	Weight   int      `json:"weight"` // points per finding
+	Points   int      `json:"points"` // points lost, after the cap
+}
+
+// scored reports whether findings of the severity cost points
+func (s Severity) scored() bool {
+	return s != SeverityInfo && s != SeverityHint
+}
+
+// Rescore recomputes the score and breakdown of the result from its rules
+// with the scoring it was created with, e.g. after rules were removed
+func (r *Result) Rescore() {
+	r.Score, r.Breakdown = r.config.score(r.Rules)
+}
+
+// weight returns the points a finding of the rule costs
+func (c *Config) weight(code string, severity Severity) int {
+	if c != nil {
+		if weight := c.Rules[code].Weight; weight != nil {
+			return *weight
+		}
+		if c.Scoring != nil {
+			if weight, ok := c.Scoring.Weights[severity]; ok {
+				return weight
+			}
+		}
+	}
+	return defaultWeights[severity]
+}
+
+// cap returns the most points the findings of a rule code may cost, or 0
+func (c *Config) cap(code string, severity Severity) int {
+	if c == nil {
+		return 0
+	}
+	if limit := c.Rules[code].Cap; limit != nil {
+		return *limit
+	}
+	if c.Scoring != nil && severity != SeverityFatal {
+		return c.Scoring.Cap
+	}
+	return 0
+}
+
+// score computes the score of the rules and the points lost to each rule
+// code, the most expensive first. Info and hint rules are ignored.
+func (c *Config) score(rules []Rule) (int, []ScoreItem) {
+	type key struct {
+		code     string
+		severity Severity
+	}
+
+	items := map[key]*ScoreItem{}
+	var order []key
+	for _, rule := range rules {
+		if !rule.Severity.scored() {
+			continue
+		}
+		k := key{rule.Code, rule.Severity}
+		item, ok := items[k]
+		if !ok {
+			item = &ScoreItem{Code: rule.Code, Severity: rule.Severity, Weight: c.weight(rule.Code, rule.Severity)}
+			items[k] = item
+			order = append(order, k)
+		}
+		item.Count++
+	}
+
+	breakdown := make([]ScoreItem, 0, len(order))
+	lost := 0
+	for _, k := range order {
+		item := items[k]
+		item.Points = item.Count * item.Weight
+		if limit := c.cap(item.Code, item.Severity); limit > 0 && item.Points > limit {
+			item.Points = limit
+		}
+		lost += item.Points
+		breakdown = append(breakdown, *item)
+	}
+
+	sort.SliceStable(breakdown, func(i, j int) bool {
+		return breakdown[i].Points > breakdown[j].Points
+	})
+
+	return max(100-lost, 0), breakdown
+}
+
+// calculateScore calculates the Dockerfile score with the default scoring
+// Score = 100 - (errors × 15 + warnings × 5), minimum 0
+// If any fatal rule is found, score is 0
+func calculateScore(rules []Rule) int {
+	score, _ := (*Config)(nil).score(rules)
+	return score
+}
@@ -32,6 +32,15 @@ func TestCalculateScore(t *testing.T) {
 			},
 			expectedScore: 95, // 100 - 5 = 95
 		},
+		{
+			name: "info and hint rules don't cost points",
+			rules: []Rule{
+				{Code: "WorkdirRelativePath", Severity: SeverityInfo},
+				{Code: "JSONArgsRecommended", Severity: SeverityHint},
+				{Code: "StageNameCasing", Severity: SeverityWarning},
+			},
+			expectedScore: 95,
+		},
 		{
 			name: "multiple errors",
 			rules: []Rule{
@@ -233,3 +242,107 @@ CMD ["echo", "hello"]`
 	require.NoError(t, err)
 	require.Equal(t, 0, result.Score, "Dockerfile with UnrecognizedInstruction should have score 0")
 }
+
+func TestConfigScore(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	rules := []Rule{
+		{Code: "SecretsUsedInArgOrEnv", Severity: SeverityWarning},
+		{Code: "StageNameCasing", Severity: SeverityWarning},
+		{Code: "StageNameCasing", Severity: SeverityWarning},
+		{Code: "StageNameCasing", Severity: SeverityWarning},
+		{Code: "UndefinedVar", Severity: SeverityError},
+	}
+
+	tests := []struct {
+		name              string
+		config            *Config
+		rules             []Rule
+		expectedScore     int
+		expectedBreakdown []ScoreItem
+	}{
+		{
+			name:          "default weights",
+			rules:         rules,
+			expectedScore: 65, // 100 - 15 - 4 × 5
+			expectedBreakdown: []ScoreItem{
+				{Code: "StageNameCasing", Severity: SeverityWarning, Count: 3, Weight: 5, Points: 15},
+				{Code: "UndefinedVar", Severity: SeverityError, Count: 1, Weight: 15, Points: 15},
+				{Code: "SecretsUsedInArgOrEnv", Severity: SeverityWarning, Count: 1, Weight: 5, Points: 5},
+			},
+		},
+		{
+			name: "severity and rule weights",
+			config: &Config{
+				Scoring: &Scoring{Weights: map[Severity]int{SeverityWarning: 2}},
+				Rules:   map[string]RuleConfig{"SecretsUsedInArgOrEnv": {Weight: intPtr(40)}},
+			},
+			rules:         rules,
+			expectedScore: 39, // 100 - 40 - 15 - 3 × 2
+			expectedBreakdown: []ScoreItem{
+				{Code: "SecretsUsedInArgOrEnv", Severity: SeverityWarning, Count: 1, Weight: 40, Points: 40},
+				{Code: "UndefinedVar", Severity: SeverityError, Count: 1, Weight: 15, Points: 15},
+				{Code: "StageNameCasing", Severity: SeverityWarning, Count: 3, Weight: 2, Points: 6},
+			},
+		},
+		{
+			name: "scoring cap and rule cap",
+			config: &Config{
+				Scoring: &Scoring{Cap: 10},
+				Rules:   map[string]RuleConfig{"StageNameCasing": {Cap: intPtr(5)}},
+			},
+			rules:         rules,
+			expectedScore: 80, // 100 - 10 - 5 - 5
+			expectedBreakdown: []ScoreItem{
+				{Code: "UndefinedVar", Severity: SeverityError, Count: 1, Weight: 15, Points: 10},
+				{Code: "SecretsUsedInArgOrEnv", Severity: SeverityWarning, Count: 1, Weight: 5, Points: 5},
+				{Code: "StageNameCasing", Severity: SeverityWarning, Count: 3, Weight: 5, Points: 5},
+			},
+		},
+		{
+			name:          "fatal drops the score to 0 by default",
+			config:        &Config{Scoring: &Scoring{Cap: 10}},
+			rules:         []Rule{{Code: "UnrecognizedInstruction", Severity: SeverityFatal}},
+			expectedScore: 0,
+			expectedBreakdown: []ScoreItem{
+				{Code: "UnrecognizedInstruction", Severity: SeverityFatal, Count: 1, Weight: 100, Points: 100},
+			},
+		},
+		{
+			name:          "fatal weight",
+			config:        &Config{Scoring: &Scoring{Weights: map[Severity]int{SeverityFatal: 30}}},
+			rules:         []Rule{{Code: "UnrecognizedInstruction", Severity: SeverityFatal}},
+			expectedScore: 70,
+			expectedBreakdown: []ScoreItem{
+				{Code: "UnrecognizedInstruction", Severity: SeverityFatal, Count: 1, Weight: 30, Points: 30},
+			},
+		},
+		{
+			name:              "no rules",
+			config:            &Config{Scoring: &Scoring{Cap: 10}},
+			expectedScore:     100,
+			expectedBreakdown: []ScoreItem{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, breakdown := tt.config.score(tt.rules)
+			require.Equal(t, tt.expectedScore, score)
+			require.Equal(t, tt.expectedBreakdown, breakdown)
+		})
+	}
+}
+
+func TestResultRescore(t *testing.T) {
+	config := &Config{Scoring: &Scoring{Weights: map[Severity]int{SeverityWarning: 10}}}
+	result, err := ParseDockerfileWithConfig("FROM alpine\nWORKDIR app\nMAINTAINER me\n", config)
+	require.NoError(t, err)
+	require.Equal(t, 80, result.Score)
+	require.Len(t, result.Breakdown, 2)
+
+	result.Rules = result.Rules[:1]
+	result.Rescore()
+	require.Equal(t, 90, result.Score, "the configured weights should be kept")
+	require.Len(t, result.Breakdown, 1)
+}
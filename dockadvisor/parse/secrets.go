@@ -2,50 +2,98 @@ package parse
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("SecretsUsedInArgOrEnv", CheckMetadata{
+		Description: "Checks for secrets passed in ARG and ENV instructions",
+		Rules: []RuleMetadata{
+			// tokens: extra name tokens considered sensitive, e.g. "dsn"
+			// allow: extra name tokens that make a name non-sensitive, e.g. "example"
+			{Code: "SecretsUsedInArgOrEnv", Severity: SeverityWarning, BuildKit: true, Options: []string{"allow", "tokens"}},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkSecretsInArgOrEnv(node, ctx.Config)
+	}))
+}
+
 var (
 	secretsRegexp      *regexp.Regexp
 	secretsAllowRegexp *regexp.Regexp
 	secretsRegexpOnce  sync.Once
 )
 
+// secretTokens are the name tokens that suggest a variable holds a secret
+var secretTokens = []string{
+	"apikey",
+	"auth",
+	"credential",
+	"credentials",
+	"key",
+	"password",
+	"pword",
+	"passwd",
+	"secret",
+	"token",
+}
+
+// secretAllowTokens are the name tokens that mark a variable as non-sensitive
+var secretAllowTokens = []string{
+	"public",
+}
+
 // getSecretsRegex returns compiled regex patterns for detecting secrets in variable names.
 // The deny pattern matches common secret-related tokens (case insensitive) at word boundaries.
 // The allow pattern matches tokens that should be excluded from secret detection.
 func getSecretsRegex() (*regexp.Regexp, *regexp.Regexp) {
-	// Check for either full value or first/last word.
-	// Examples: api_key, DATABASE_PASSWORD, GITHUB_TOKEN, secret_MESSAGE, AUTH
-	// Case insensitive.
 	secretsRegexpOnce.Do(func() {
-		secretTokens := []string{
-			"apikey",
-			"auth",
-			"credential",
-			"credentials",
-			"key",
-			"password",
-			"pword",
-			"passwd",
-			"secret",
-			"token",
-		}
-		pattern := `(?i)(?:_|^)(?:` + strings.Join(secretTokens, "|") + `)(?:_|$)`
-		secretsRegexp = regexp.MustCompile(pattern)
-
-		allowTokens := []string{
-			"public",
-		}
-		allowPattern := `(?i)(?:_|^)(?:` + strings.Join(allowTokens, "|") + `)(?:_|$)`
-		secretsAllowRegexp = regexp.MustCompile(allowPattern)
+		secretsRegexp = secretsTokenRegexp(secretTokens)
+		secretsAllowRegexp = secretsTokenRegexp(secretAllowTokens)
 	})
 	return secretsRegexp, secretsAllowRegexp
 }
 
+// secretsTokenRegexp builds a pattern matching any of the tokens as the full
+// name or as one of its underscore-separated words.
+// Examples: api_key, DATABASE_PASSWORD, GITHUB_TOKEN, secret_MESSAGE, AUTH
+// Case insensitive.
+func secretsTokenRegexp(tokens []string) *regexp.Regexp {
+	quoted := make([]string, len(tokens))
+	for i, token := range tokens {
+		quoted[i] = regexp.QuoteMeta(token)
+	}
+	return regexp.MustCompile(`(?i)(?:_|^)(?:` + strings.Join(quoted, "|") + `)(?:_|$)`)
+}
+
+// secretsMatcher reports whether variable names suggest sensitive data,
+// using the default tokens extended by the rule options
+type secretsMatcher struct {
+	deny, allow *regexp.Regexp
+}
+
+func newSecretsMatcher(config *Config) secretsMatcher {
+	tokens := config.option("SecretsUsedInArgOrEnv", "tokens")
+	allowTokens := config.option("SecretsUsedInArgOrEnv", "allow")
+
+	deny, allow := getSecretsRegex()
+	if len(tokens) != 0 {
+		deny = secretsTokenRegexp(append(slices.Clone(secretTokens), tokens...))
+	}
+	if len(allowTokens) != 0 {
+		allow = secretsTokenRegexp(append(slices.Clone(secretAllowTokens), allowTokens...))
+	}
+	return secretsMatcher{deny: deny, allow: allow}
+}
+
+func (m secretsMatcher) sensitive(varName string) bool {
+	return m.deny.MatchString(varName) && !m.allow.MatchString(varName)
+}
+
 // checkSecretsInArgOrEnv validates that sensitive data is not exposed through
 // ARG or ENV instructions in Dockerfiles.
 //
@@ -58,12 +106,13 @@ func getSecretsRegex() (*regexp.Regexp, *regexp.Regexp) {
 // - SECRET, PASSWORD, TOKEN, KEY
 // - AWS credentials (AWS_SECRET_ACCESS_KEY, AWS_ACCESS_KEY_ID)
 // - Common patterns (API_KEY, PRIVATE_KEY, AUTH_TOKEN, etc.)
-func checkSecretsInArgOrEnv(ast *parser.Node) []Rule {
+func checkSecretsInArgOrEnv(ast *parser.Node, config *Config) []Rule {
 	if ast == nil || len(ast.Children) == 0 {
 		return nil
 	}
 
 	var rules []Rule
+	matcher := newSecretsMatcher(config)
 
 	for _, child := range ast.Children {
 		instruction := strings.ToUpper(child.Value)
@@ -74,7 +123,7 @@ func checkSecretsInArgOrEnv(ast *parser.Node) []Rule {
 			varNames := extractVariableNamesFromInstruction(child)
 
 			for _, varName := range varNames {
-				if isSensitiveVariableName(varName) {
+				if matcher.sensitive(varName) {
 					rules = append(rules, Rule{
 						StartLine:   child.StartLine,
 						EndLine:     child.EndLine,
@@ -82,7 +131,7 @@ func checkSecretsInArgOrEnv(ast *parser.Node) []Rule {
 						Description: "Sensitive data should not be used in " + instruction + " instruction: '" + varName + "'. Consider using secret mounts instead",
 						Url:         "https://docs.docker.com/reference/build-checks/secrets-used-in-arg-or-env/",
 						Severity:    SeverityWarning,
-					})
+					}.at(varName))
 				}
 			}
 		}
@@ -121,6 +170,5 @@ func extractVariableNamesFromInstruction(node *parser.Node) []string {
 // using regex patterns that match common secret-related tokens at word boundaries.
 // Variables containing allowlisted tokens (like "public") are excluded.
 func isSensitiveVariableName(varName string) bool {
-	deny, allow := getSecretsRegex()
-	return deny.MatchString(varName) && !allow.MatchString(varName)
+	return newSecretsMatcher(nil).sensitive(varName)
 }
@@ -7,16 +7,31 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("SHELL", CheckMetadata{
+		Description:  "Checks the form of SHELL instructions",
+		Instructions: []string{"SHELL"},
+		Rules: []RuleMetadata{
+			{Code: "ShellInvalidJsonForm", Severity: SeverityError},
+			{Code: "ShellMissingConfig", Severity: SeverityError},
+			{Code: "ShellRequiresJsonForm", Severity: SeverityWarning},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseSHELL(node)
+	}))
+}
+
 func parseSHELL(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "SHELL requires at least one argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// Extract shell configuration
 	shellConfig := extractSHELLConfig(node)
 
-	// Validate that config is not empty
+	// Each check below needs the previous one to pass: there is no form to
+	// check without a configuration, nor JSON to validate in the shell form
 	if strings.TrimSpace(shellConfig) == "" {
 		return []Rule{NewErrorRule(node, "ShellMissingConfig",
 			"SHELL instruction must specify a shell configuration",
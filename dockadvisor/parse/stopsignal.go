@@ -6,12 +6,24 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("STOPSIGNAL", CheckMetadata{
+		Description:  "Checks STOPSIGNAL instructions",
+		Instructions: []string{"STOPSIGNAL"},
+		Rules: []RuleMetadata{
+			{Code: "StopsignalMissingValue", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseSTOPSIGNAL(node)
+	}))
+}
+
 func parseSTOPSIGNAL(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "STOPSIGNAL requires a signal argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// Extract signal value
 	signal := strings.TrimSpace(node.Next.Value)
 
@@ -0,0 +1,176 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+const unusedSuppressionCode = "UnusedSuppression"
+
+// suppressionRegexp matches "# dockadvisor ignore=Code1,Code2" and
+// "# dockadvisor ignore-file=Code" directives on a line of their own
+var suppressionRegexp = regexp.MustCompile(`^\s*#\s*dockadvisor\s+(ignore|ignore-file)=([A-Za-z0-9]+(?:\s*,\s*[A-Za-z0-9]+)*)`)
+
+// trailingSuppressionRegexp matches a "# dockadvisor ignore=Code1,Code2"
+// directive that ends the line of an instruction. It is only a directive when
+// its # isn't quoted, see unquoted.
+var trailingSuppressionRegexp = regexp.MustCompile(`\s#\s*dockadvisor\s+(ignore)=([A-Za-z0-9]+(?:\s*,\s*[A-Za-z0-9]+)*)\s*$`)
+
+// codeRegexp matches a rule code of a directive
+var codeRegexp = regexp.MustCompile(`[A-Za-z0-9]+`)
+
+// suppression is a single code listed in a suppression directive
+type suppression struct {
+	code string
+	line int // line of the directive
+
+	// startColumn and endColumn delimit the code on the line of the
+	// directive
+	startColumn, endColumn int
+
+	// startLine and endLine delimit the instruction the directive applies
+	// to; both are 0 for file-level directives
+	startLine, endLine int
+	file               bool
+
+	used bool
+}
+
+// matches reports whether the suppression applies to rule
+func (s *suppression) matches(rule Rule) bool {
+	if rule.Code != s.code {
+		return false
+	}
+	return s.file || rule.StartLine >= s.startLine && rule.StartLine <= s.endLine && s.startLine > 0
+}
+
+// suppressRules splits rules into those still reported and those silenced
+// by a suppression directive. A directive on its own line applies to the
+// next instruction, a directive trailing an instruction to that instruction,
+// and ignore-file to the whole Dockerfile. Codes that suppress nothing are
+// reported as UnusedSuppression warnings.
+func suppressRules(ast *parser.Node, rules []Rule, dockerfileContent string) ([]Rule, []Rule) {
+	suppressions := findSuppressions(ast, dockerfileContent)
+	if len(suppressions) == 0 {
+		return rules, nil
+	}
+
+	var kept, suppressed []Rule
+	for _, rule := range rules {
+		silenced := false
+		for i := range suppressions {
+			if suppressions[i].matches(rule) {
+				suppressions[i].used = true
+				silenced = true
+			}
+		}
+		if silenced {
+			suppressed = append(suppressed, rule)
+		} else {
+			kept = append(kept, rule)
+		}
+	}
+
+	for _, s := range suppressions {
+		if s.used {
+			continue
+		}
+		kept = append(kept, Rule{
+			StartLine:   s.line,
+			EndLine:     s.line,
+			StartColumn: s.startColumn,
+			EndColumn:   s.endColumn,
+			Code:        unusedSuppressionCode,
+			Description: fmt.Sprintf("Suppression of %s does not match any finding and can be removed", s.code),
+			Severity:    SeverityWarning,
+		})
+	}
+
+	return kept, suppressed
+}
+
+// findSuppressions collects the suppression directives of a Dockerfile
+func findSuppressions(ast *parser.Node, dockerfileContent string) []suppression {
+	lines := strings.Split(dockerfileContent, "\n")
+
+	var suppressions []suppression
+	for i, line := range lines {
+		match := suppressionRegexp.FindStringSubmatchIndex(line)
+		trailing := false
+		if match == nil {
+			// The parser leaves the comments at the end of a line in the
+			// arguments, so the directive is read from the line itself
+			match = trailingSuppressionRegexp.FindStringSubmatchIndex(line)
+			if match == nil || strings.HasPrefix(strings.TrimSpace(line), "#") || !unquoted(line[:match[0]]) {
+				continue
+			}
+			trailing = true
+		}
+		directive := line[match[2]:match[3]]
+
+		s := suppression{line: i + 1}
+		switch {
+		case directive == "ignore-file":
+			s.file = true
+		case trailing:
+			s.startLine, s.endLine = instructionLines(ast, i+1)
+		default:
+			// Applies to the instruction on the next line that isn't blank
+			// or a comment
+			for j := i + 1; j < len(lines); j++ {
+				next := strings.TrimSpace(lines[j])
+				if next != "" && !strings.HasPrefix(next, "#") {
+					s.startLine, s.endLine = instructionLines(ast, j+1)
+					break
+				}
+			}
+		}
+
+		for _, code := range codeRegexp.FindAllStringIndex(line[match[4]:match[5]], -1) {
+			start, end := match[4]+code[0], match[4]+code[1]
+			s.code = line[start:end]
+			s.startColumn = utf8.RuneCountInString(line[:start]) + 1
+			s.endColumn = utf8.RuneCountInString(line[:end]) + 1
+			suppressions = append(suppressions, s)
+		}
+	}
+
+	return suppressions
+}
+
+// unquoted reports whether the end of text is outside of any quoted string,
+// so that a # following it starts a comment rather than being part of a
+// string such as the argument of an echo
+func unquoted(text string) bool {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && quote != '\'':
+			i++
+		case quote != 0:
+			if text[i] == quote {
+				quote = 0
+			}
+		case text[i] == '"' || text[i] == '\'':
+			quote = text[i]
+		}
+	}
+	return quote == 0
+}
+
+// instructionLines returns the line range of the instruction spanning line
+// n, or n, n when no instruction does
+func instructionLines(ast *parser.Node, n int) (int, int) {
+	if ast != nil {
+		for _, child := range ast.Children {
+			if child.StartLine <= n && n <= child.EndLine {
+				return child.StartLine, child.EndLine
+			}
+		}
+	}
+	return n, n
+}
@@ -0,0 +1,202 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressions(t *testing.T) {
+	tests := []struct {
+		name               string
+		dockerfileContent  string
+		expectedCodes      []string
+		expectedSuppressed []string
+		expectedScore      int
+	}{
+		{
+			name: "preceding line",
+			dockerfileContent: `FROM alpine AS base
+# dockadvisor ignore=StageNameCasing
+FROM base AS Builder
+WORKDIR app`,
+			expectedCodes:      []string{"WorkdirRelativePath"},
+			expectedSuppressed: []string{"StageNameCasing"},
+			expectedScore:      95,
+		},
+		{
+			name: "preceding line skips blank lines and comments",
+			dockerfileContent: `FROM alpine
+# dockadvisor ignore=WorkdirRelativePath
+
+# the application lives next to the binary
+WORKDIR app`,
+			expectedCodes:      []string{},
+			expectedSuppressed: []string{"WorkdirRelativePath"},
+			expectedScore:      100,
+		},
+		{
+			name: "preceding line only applies to the next instruction",
+			dockerfileContent: `FROM alpine
+# dockadvisor ignore=WorkdirRelativePath
+WORKDIR app
+WORKDIR lib`,
+			expectedCodes:      []string{"WorkdirRelativePath"},
+			expectedSuppressed: []string{"WorkdirRelativePath"},
+			expectedScore:      95,
+		},
+		{
+			name: "trailing form",
+			dockerfileContent: `FROM alpine
+RUN --network=bridge \
+    make # dockadvisor ignore=RunInvalidNetworkFlag`,
+			expectedCodes:      []string{},
+			expectedSuppressed: []string{"RunInvalidNetworkFlag"},
+			expectedScore:      100,
+		},
+		{
+			name: "trailing form only applies to its instruction",
+			dockerfileContent: `FROM alpine
+WORKDIR app # dockadvisor ignore=WorkdirRelativePath
+WORKDIR lib`,
+			expectedCodes:      []string{"WorkdirRelativePath"},
+			expectedSuppressed: []string{"WorkdirRelativePath"},
+			expectedScore:      95,
+		},
+		{
+			name: "unused trailing suppression",
+			dockerfileContent: `FROM alpine
+WORKDIR /app # dockadvisor ignore=WorkdirRelativePath
+WORKDIR lib`,
+			expectedCodes:      []string{"UnusedSuppression", "WorkdirRelativePath"},
+			expectedSuppressed: []string{},
+			expectedScore:      90,
+		},
+		{
+			name: "trailing directive in a quoted string",
+			dockerfileContent: `FROM alpine
+RUN echo "done # dockadvisor ignore=WorkdirRelativePath"
+WORKDIR app # dockadvisor ignore=JSONArgsRecommended`,
+			expectedCodes:      []string{"WorkdirRelativePath", "UnusedSuppression"},
+			expectedSuppressed: []string{},
+			expectedScore:      90,
+		},
+		{
+			name: "directive in a RUN string",
+			dockerfileContent: `FROM alpine
+RUN echo "# dockadvisor ignore=WorkdirRelativePath" > /notes
+WORKDIR app`,
+			expectedCodes:      []string{"WorkdirRelativePath"},
+			expectedSuppressed: []string{},
+			expectedScore:      95,
+		},
+		{
+			name: "directive on a comment line inside a continuation",
+			dockerfileContent: `FROM alpine
+RUN apk add curl \
+    # dockadvisor ignore=WorkdirRelativePath
+    && true
+WORKDIR app`,
+			expectedCodes:      []string{"WorkdirRelativePath", "UnusedSuppression"},
+			expectedSuppressed: []string{},
+			expectedScore:      90,
+		},
+		{
+			name: "several codes",
+			dockerfileContent: `FROM alpine
+# dockadvisor ignore=MaintainerDeprecated, UndefinedVar
+MAINTAINER $AUTHOR`,
+			expectedCodes:      []string{},
+			expectedSuppressed: []string{"MaintainerDeprecated", "UndefinedVar"},
+			expectedScore:      100,
+		},
+		{
+			name: "file level",
+			dockerfileContent: `# dockadvisor ignore-file=WorkdirRelativePath
+FROM alpine
+WORKDIR app
+WORKDIR lib`,
+			expectedCodes:      []string{},
+			expectedSuppressed: []string{"WorkdirRelativePath", "WorkdirRelativePath"},
+			expectedScore:      100,
+		},
+		{
+			name: "unused suppression",
+			dockerfileContent: `FROM alpine
+# dockadvisor ignore=WorkdirRelativePath
+WORKDIR /app`,
+			expectedCodes:      []string{"UnusedSuppression"},
+			expectedSuppressed: []string{},
+			expectedScore:      95,
+		},
+		{
+			name: "unused file level suppression",
+			dockerfileContent: `# dockadvisor ignore-file=MaintainerDeprecated
+FROM alpine`,
+			expectedCodes:      []string{"UnusedSuppression"},
+			expectedSuppressed: []string{},
+			expectedScore:      95,
+		},
+		{
+			name: "suppression at the end of the file",
+			dockerfileContent: `FROM alpine
+WORKDIR app
+# dockadvisor ignore=WorkdirRelativePath`,
+			expectedCodes:      []string{"WorkdirRelativePath", "UnusedSuppression"},
+			expectedSuppressed: []string{},
+			expectedScore:      90,
+		},
+		{
+			name: "fatal rules can be suppressed",
+			dockerfileContent: `FROM alpine
+# dockadvisor ignore=UnrecognizedInstruction
+FOOBAR baz`,
+			expectedCodes:      []string{},
+			expectedSuppressed: []string{"UnrecognizedInstruction"},
+			expectedScore:      100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDockerfile(tt.dockerfileContent)
+			require.NoError(t, err)
+
+			codes := []string{}
+			for _, rule := range result.Rules {
+				codes = append(codes, rule.Code)
+			}
+			suppressed := []string{}
+			for _, rule := range result.Suppressed {
+				suppressed = append(suppressed, rule.Code)
+			}
+
+			require.ElementsMatch(t, tt.expectedCodes, codes)
+			require.ElementsMatch(t, tt.expectedSuppressed, suppressed)
+			require.Equal(t, tt.expectedScore, result.Score)
+		})
+	}
+}
+
+func TestUnusedSuppressionLocation(t *testing.T) {
+	result, err := ParseDockerfile("FROM alpine\n# dockadvisor ignore=WorkdirRelativePath,JSONArgsRecommended\nWORKDIR app\n")
+	require.NoError(t, err)
+	require.Len(t, result.Rules, 1)
+
+	rule := result.Rules[0]
+	require.Equal(t, "UnusedSuppression", rule.Code)
+	require.Equal(t, SeverityWarning, rule.Severity)
+	require.Equal(t, "Suppression of JSONArgsRecommended does not match any finding and can be removed", rule.Description)
+	require.Equal(t, [4]int{2, 2, 42, 61}, [4]int{rule.StartLine, rule.EndLine, rule.StartColumn, rule.EndColumn})
+}
+
+func TestUnusedTrailingSuppressionLocation(t *testing.T) {
+	// The code also appears in the arguments, before the directive
+	result, err := ParseDockerfile("FROM alpine\nRUN echo StageNameCasing # dockadvisor ignore=StageNameCasing\n")
+	require.NoError(t, err)
+	require.Len(t, result.Rules, 1)
+
+	rule := result.Rules[0]
+	require.Equal(t, "UnusedSuppression", rule.Code)
+	require.Equal(t, [4]int{2, 2, 47, 62}, [4]int{rule.StartLine, rule.EndLine, rule.StartColumn, rule.EndColumn})
+}
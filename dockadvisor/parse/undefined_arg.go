@@ -7,6 +7,17 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("UndefinedArgInFrom", CheckMetadata{
+		Description: "Checks that FROM only references declared ARGs",
+		Rules: []RuleMetadata{
+			{Code: "UndefinedArgInFrom", Severity: SeverityError, BuildKit: true, FileScoped: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkUndefinedArgInFrom(node)
+	}))
+}
+
 // checkUndefinedArgInFrom checks that FROM instructions only reference ARGs that have been declared.
 // ARG instructions before the first FROM are in global scope and can be used in FROM instructions.
 // Docker also provides predefined ARGs like TARGETPLATFORM, BUILDPLATFORM, etc.
@@ -81,7 +92,7 @@ func checkUndefinedArgInFrom(ast *parser.Node) []Rule {
 						Description: "FROM argument '" + varRef + "' is not declared",
 						Url:         "https://docs.docker.com/reference/build-checks/undefined-arg-in-from/",
 						Severity:    SeverityError,
-					})
+					}.atVariable(varRef))
 				}
 			}
 		}
@@ -6,6 +6,17 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("UndefinedVar", CheckMetadata{
+		Description: "Checks that variables are declared before they are used",
+		Rules: []RuleMetadata{
+			{Code: "UndefinedVar", Severity: SeverityError, BuildKit: true, FileScoped: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkUndefinedVar(node)
+	}))
+}
+
 // checkUndefinedVar validates that all variable references are declared before use.
 // This check applies to all instructions except shell form RUN, CMD, and ENTRYPOINT,
 // where variables are resolved by the command shell at runtime.
@@ -95,7 +106,7 @@ func checkUndefinedVar(ast *parser.Node) []Rule {
 						Description: "Usage of undefined variable '$" + varName + "'",
 						Url:         "https://docs.docker.com/reference/build-checks/undefined-var/",
 						Severity:    SeverityError,
-					})
+					}.atVariable(varName))
 				}
 			}
 
@@ -110,7 +121,7 @@ func checkUndefinedVar(ast *parser.Node) []Rule {
 							Code:        "UndefinedVar",
 							Description: "Usage of undefined variable '$" + varName + "'",
 							Url:         "https://docs.docker.com/reference/build-checks/undefined-var/",
-						})
+						}.atVariable(varName))
 					}
 				}
 			}
@@ -145,7 +156,7 @@ func checkUndefinedVar(ast *parser.Node) []Rule {
 									Code:        "UndefinedVar",
 									Description: "Usage of undefined variable '$" + varName + "'",
 									Url:         "https://docs.docker.com/reference/build-checks/undefined-var/",
-								})
+								}.atVariable(varName))
 							}
 						}
 					}
@@ -188,7 +199,7 @@ func checkUndefinedVar(ast *parser.Node) []Rule {
 								Code:        "UndefinedVar",
 								Description: "Usage of undefined variable '$" + varName + "'",
 								Url:         "https://docs.docker.com/reference/build-checks/undefined-var/",
-							})
+							}.atVariable(varName))
 						}
 					}
 				}
@@ -225,7 +236,7 @@ func checkUndefinedVar(ast *parser.Node) []Rule {
 									Code:        "UndefinedVar",
 									Description: "Usage of undefined variable '$" + varName + "'",
 									Url:         "https://docs.docker.com/reference/build-checks/undefined-var/",
-								})
+								}.atVariable(varName))
 							}
 						}
 						current = current.Next
@@ -249,7 +260,7 @@ func checkUndefinedVar(ast *parser.Node) []Rule {
 						Description: "Usage of undefined variable '$" + varName + "'",
 						Url:         "https://docs.docker.com/reference/build-checks/undefined-var/",
 						Severity:    SeverityError,
-					})
+					}.atVariable(varName))
 				}
 			}
 
@@ -264,7 +275,7 @@ func checkUndefinedVar(ast *parser.Node) []Rule {
 							Code:        "UndefinedVar",
 							Description: "Usage of undefined variable '$" + varName + "'",
 							Url:         "https://docs.docker.com/reference/build-checks/undefined-var/",
-						})
+						}.atVariable(varName))
 					}
 				}
 			}
@@ -284,7 +295,7 @@ func checkUndefinedVar(ast *parser.Node) []Rule {
 						Description: "Usage of undefined variable '$" + varName + "'",
 						Url:         "https://docs.docker.com/reference/build-checks/undefined-var/",
 						Severity:    SeverityError,
-					})
+					}.atVariable(varName))
 				}
 			}
 		}
@@ -0,0 +1,60 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+func init() {
+	Register(NewCheck("UnrecognizedInstruction", CheckMetadata{
+		Description: "Checks that every instruction is a Dockerfile instruction",
+		Rules: []RuleMetadata{
+			{Code: "UnrecognizedInstruction", Severity: SeverityFatal},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkUnrecognizedInstructions(node)
+	}))
+}
+
+// dockerfileInstructions lists the instructions of the Dockerfile reference
+var dockerfileInstructions = map[string]bool{
+	"ADD":         true,
+	"ARG":         true,
+	"CMD":         true,
+	"COPY":        true,
+	"ENTRYPOINT":  true,
+	"ENV":         true,
+	"EXPOSE":      true,
+	"FROM":        true,
+	"HEALTHCHECK": true,
+	"LABEL":       true,
+	"MAINTAINER":  true,
+	"ONBUILD":     true,
+	"RUN":         true,
+	"SHELL":       true,
+	"STOPSIGNAL":  true,
+	"USER":        true,
+	"VOLUME":      true,
+	"WORKDIR":     true,
+}
+
+// checkUnrecognizedInstructions reports the instructions that aren't
+// Dockerfile instructions
+func checkUnrecognizedInstructions(ast *parser.Node) []Rule {
+	if ast == nil {
+		return nil
+	}
+
+	var rules []Rule
+	for _, child := range ast.Children {
+		if dockerfileInstructions[strings.ToUpper(child.Value)] {
+			continue
+		}
+		rules = append(rules, NewFatalRule(child, "UnrecognizedInstruction",
+			fmt.Sprintf("'%s' is not a recognized Dockerfile instruction", child.Value),
+			"https://docs.docker.com/reference/dockerfile/").atKeyword())
+	}
+	return rules
+}
@@ -2,21 +2,84 @@ package parse
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("USER", CheckMetadata{
+		Description:  "Checks the format of USER instructions",
+		Instructions: []string{"USER"},
+		Rules: []RuleMetadata{
+			{Code: "UserInvalidFormat", Severity: SeverityError},
+			{Code: "UserMissingValue", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseUSER(node)
+	}))
+	Register(NewCheck("UserRoot", CheckMetadata{
+		Description: "Checks that the stage being built doesn't switch to the root user",
+		Rules: []RuleMetadata{
+			{Code: "UserRoot", Severity: SeverityWarning, FileScoped: true, OptIn: true},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return checkUserRoot(node, ctx.Target)
+	}))
+}
+
+// checkUserRoot reports the last USER of the stage being built when it is
+// root, since the container then runs with root privileges. That stage is
+// the target stage, given by name or index, or the final stage without a
+// target. Stages that don't set a user are left alone: their user comes from
+// the base image.
+func checkUserRoot(ast *parser.Node, target string) []Rule {
+	if ast == nil {
+		return nil
+	}
+
+	var last *parser.Node
+	built := false
+	index := -1
+	for _, child := range ast.Children {
+		switch strings.ToUpper(child.Value) {
+		case "FROM":
+			index++
+			_, name, _ := extractFromComponents(child)
+			built = target == "" || strings.EqualFold(name, target) || target == strconv.Itoa(index)
+			if built {
+				last = nil
+			}
+		case "USER":
+			if built {
+				last = child
+			}
+		}
+	}
+	if last == nil || last.Next == nil {
+		return nil
+	}
+
+	user, _, _ := strings.Cut(extractUSERConfig(last), ":")
+	if user != "root" && user != "0" {
+		return nil
+	}
+	return []Rule{NewWarningRule(last, "UserRoot",
+		"The image runs as '"+user+"'. Switch to an unprivileged user so that the container doesn't run with root privileges",
+		"https://docs.docker.com/build/building/best-practices/#user").at(user)}
+}
+
 func parseUSER(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "USER requires at least one argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// Extract user configuration
 	userConfig := extractUSERConfig(node)
 
-	// Validate that config is not empty
+	// Without a user there is no format to check
 	if strings.TrimSpace(userConfig) == "" {
 		return []Rule{NewErrorRule(node, "UserMissingValue",
 			"USER instruction must specify a user",
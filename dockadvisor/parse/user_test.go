@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -234,3 +235,80 @@ func TestParseUSER(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckUserRoot(t *testing.T) {
+	tests := []struct {
+		name              string
+		dockerfileContent string
+		target            string
+		expectedLine      int // line of the finding, 0 for none
+	}{
+		{
+			name:              "final stage runs as root",
+			dockerfileContent: "FROM alpine\nUSER root\n",
+			expectedLine:      2,
+		},
+		{
+			name:              "root UID with a group",
+			dockerfileContent: "FROM alpine\nUSER 0:0\n",
+			expectedLine:      2,
+		},
+		{
+			name:              "switches back to an unprivileged user",
+			dockerfileContent: "FROM alpine\nUSER root\nRUN apk add curl\nUSER app\n",
+		},
+		{
+			name:              "root in a build stage",
+			dockerfileContent: "FROM golang AS build\nUSER root\nFROM alpine\nUSER app\n",
+		},
+		{
+			name:              "final stage without USER",
+			dockerfileContent: "FROM golang AS build\nUSER root\nFROM alpine\n",
+		},
+		{
+			name:              "user named like root",
+			dockerfileContent: "FROM alpine\nUSER rootless\n",
+		},
+		{
+			name:              "target stage runs as root",
+			dockerfileContent: "FROM golang AS build\nUSER root\nFROM alpine\nUSER app\n",
+			target:            "build",
+			expectedLine:      2,
+		},
+		{
+			name:              "target stage given by index",
+			dockerfileContent: "FROM golang AS build\nUSER root\nFROM alpine\nUSER app\n",
+			target:            "0",
+			expectedLine:      2,
+		},
+		{
+			name:              "root in the final stage but not in the target",
+			dockerfileContent: "FROM alpine AS app\nUSER app\nFROM app\nUSER root\n",
+			target:            "APP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Lint(context.Background(), LintInput{Content: tt.dockerfileContent, Target: tt.target},
+				Options{Config: &Config{Preset: "security"}, Rules: []string{"UserRoot"}})
+			require.NoError(t, err)
+
+			lines := []int{}
+			for _, rule := range result.Rules {
+				lines = append(lines, rule.StartLine)
+			}
+			if tt.expectedLine == 0 {
+				require.Empty(t, lines)
+			} else {
+				require.Equal(t, []int{tt.expectedLine}, lines)
+			}
+		})
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		result, err := ParseDockerfile("FROM alpine\nUSER root\n")
+		require.NoError(t, err)
+		require.Empty(t, result.Rules)
+	})
+}
@@ -7,16 +7,29 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("VOLUME", CheckMetadata{
+		Description:  "Checks the paths of VOLUME instructions",
+		Instructions: []string{"VOLUME"},
+		Rules: []RuleMetadata{
+			{Code: "VolumeInvalidJsonForm", Severity: SeverityError},
+			{Code: "VolumeMissingPath", Severity: SeverityError},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseVOLUME(node)
+	}))
+}
+
 func parseVOLUME(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "VOLUME requires at least one argument")}
 	}
 
-	// ERROR CHECKS - Return immediately on first error
 	// Extract volume configuration
 	volumeConfig := extractVOLUMEConfig(node)
 
-	// Validate that config is not empty
+	// Without a mount point there is no form to check
 	if strings.TrimSpace(volumeConfig) == "" {
 		return []Rule{NewErrorRule(node, "VolumeMissingPath",
 			"VOLUME instruction must specify at least one mount point",
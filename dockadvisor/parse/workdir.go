@@ -6,6 +6,19 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 )
 
+func init() {
+	Register(NewCheck("WORKDIR", CheckMetadata{
+		Description:  "Checks the path of WORKDIR instructions",
+		Instructions: []string{"WORKDIR"},
+		Rules: []RuleMetadata{
+			{Code: "WorkdirRelativePath", Severity: SeverityWarning, BuildKit: true},
+			{Code: invalidInstructionCode, Severity: SeverityError},
+		},
+	}, func(node *parser.Node, ctx *CheckContext) []Rule {
+		return parseWorkdir(node)
+	}))
+}
+
 func parseWorkdir(node *parser.Node) []Rule {
 	if node.Next == nil {
 		return []Rule{invalidInstructionRule(node, "WORKDIR requires exactly one argument")}
@@ -17,7 +30,7 @@ func parseWorkdir(node *parser.Node) []Rule {
 	if !checkWorkdirAbsolute(workdirValue.Value) {
 		workdirRules = append(workdirRules, NewWarningRule(node, "WorkdirRelativePath",
 			"WORKDIR uses a relative path. Consider using an absolute path (starting with /) to avoid issues when the base image's working directory changes.",
-			"https://docs.docker.com/reference/build-checks/workdir-relative-path/"))
+			"https://docs.docker.com/reference/build-checks/workdir-relative-path/").at(workdirValue.Value))
 	}
 
 	return workdirRules
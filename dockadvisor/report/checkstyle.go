@@ -0,0 +1,67 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+// checkstyleFormatVersion is the Checkstyle XML format version understood by
+// Jenkins, GitLab and most other consumers
+const checkstyleFormatVersion = "4.3"
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Score  int               `xml:"score,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// WriteCheckstyle writes the results as Checkstyle XML. Each Dockerfile
+// becomes a file element carrying its score as an extra attribute, and each
+// rule an error element whose source is "dockadvisor.<Code>".
+func WriteCheckstyle(w io.Writer, files []File) error {
+	out := checkstyleReport{Version: checkstyleFormatVersion}
+
+	for _, file := range files {
+		csFile := checkstyleFile{Name: file.Path, Score: file.Result.Score}
+		for _, rule := range file.Result.Rules {
+			csFile.Errors = append(csFile.Errors, checkstyleError{
+				Line:     rule.StartLine,
+				Column:   rule.StartColumn,
+				Severity: checkstyleSeverity(rule.Severity),
+				Message:  rule.Description,
+				Source:   toolName + "." + rule.Code,
+			})
+		}
+		out.Files = append(out.Files, csFile)
+	}
+
+	return writeXML(w, out)
+}
+
+// checkstyleSeverity maps a dockadvisor severity to a Checkstyle severity
+func checkstyleSeverity(severity parse.Severity) string {
+	switch severity {
+	case parse.SeverityFatal, parse.SeverityError:
+		return "error"
+	case parse.SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
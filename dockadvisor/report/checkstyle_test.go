@@ -0,0 +1,48 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCheckstyle(t *testing.T) {
+	files := []File{
+		{
+			Path: "Dockerfile",
+			Result: &parse.Result{
+				Rules: []parse.Rule{
+					{StartLine: 1, EndLine: 1, Code: "FromAsCasing", Description: "casing", Severity: parse.SeverityWarning},
+					{StartLine: 3, EndLine: 3, StartColumn: 1, EndColumn: 7, Code: "UnrecognizedInstruction", Description: "unknown <instruction>", Severity: parse.SeverityFatal},
+				},
+				Score: 0,
+			},
+		},
+		{
+			Path:   "clean.Dockerfile",
+			Result: &parse.Result{Score: 100},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCheckstyle(&buf, files))
+
+	var out checkstyleReport
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &out))
+	require.Equal(t, "4.3", out.Version)
+	require.Len(t, out.Files, 2)
+
+	file := out.Files[0]
+	require.Equal(t, "Dockerfile", file.Name)
+	require.Equal(t, 0, file.Score)
+	require.Equal(t, []checkstyleError{
+		{Line: 1, Severity: "warning", Message: "casing", Source: "dockadvisor.FromAsCasing"},
+		{Line: 3, Column: 1, Severity: "error", Message: "unknown <instruction>", Source: "dockadvisor.UnrecognizedInstruction"},
+	}, file.Errors)
+
+	require.Equal(t, 100, out.Files[1].Score)
+	require.Empty(t, out.Files[1].Errors)
+}
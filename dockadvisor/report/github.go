@@ -0,0 +1,75 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+// WriteGitHub writes one GitHub Actions workflow command per rule so that
+// each finding shows up as an annotation on the pull request
+func WriteGitHub(w io.Writer, files []File) error {
+	for _, file := range files {
+		for _, rule := range file.Result.Rules {
+			if _, err := io.WriteString(w, GitHubAnnotation(file.Path, rule)+"\n"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GitHubAnnotation formats a rule as a ::error, ::warning or ::notice
+// workflow command matching its severity
+func GitHubAnnotation(path string, rule parse.Rule) string {
+	properties := []string{"file=" + escapeGitHubProperty(filepath.ToSlash(path))}
+	if rule.StartLine > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", rule.StartLine))
+		if rule.EndLine >= rule.StartLine {
+			properties = append(properties, fmt.Sprintf("endLine=%d", rule.EndLine))
+		}
+		// GitHub only honors columns on single-line annotations, and its
+		// end column is inclusive
+		if rule.StartColumn > 0 && rule.EndLine == rule.StartLine && rule.EndColumn > rule.StartColumn {
+			properties = append(properties, fmt.Sprintf("col=%d", rule.StartColumn), fmt.Sprintf("endColumn=%d", rule.EndColumn-1))
+		}
+	}
+	properties = append(properties, "title="+escapeGitHubProperty(rule.Code))
+
+	message := rule.Description
+	if rule.Url != "" {
+		message += "\n" + rule.Url
+	}
+
+	return "::" + gitHubCommand(rule.Severity) + " " + strings.Join(properties, ",") + "::" + escapeGitHubData(message)
+}
+
+// gitHubCommand maps a dockadvisor severity to an annotation command. Info
+// and hint rules become notices.
+func gitHubCommand(severity parse.Severity) string {
+	switch severity {
+	case parse.SeverityFatal, parse.SeverityError:
+		return "error"
+	case parse.SeverityWarning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// escapeGitHubData escapes the message part of a workflow command
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
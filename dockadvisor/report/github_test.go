@@ -0,0 +1,91 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubAnnotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		rule     parse.Rule
+		expected string
+	}{
+		{
+			name:     "warning on a single line",
+			path:     "Dockerfile",
+			rule:     parse.Rule{StartLine: 2, EndLine: 2, Code: "WorkdirRelativePath", Description: "relative", Severity: parse.SeverityWarning},
+			expected: "::warning file=Dockerfile,line=2,endLine=2,title=WorkdirRelativePath::relative",
+		},
+		{
+			name:     "error with documentation link",
+			path:     "Dockerfile",
+			rule:     parse.Rule{StartLine: 3, EndLine: 5, Code: "RunMissingCommand", Description: "missing", Url: "https://example.com", Severity: parse.SeverityError},
+			expected: "::error file=Dockerfile,line=3,endLine=5,title=RunMissingCommand::missing%0Ahttps://example.com",
+		},
+		{
+			name:     "fatal is reported as an error",
+			path:     "Dockerfile",
+			rule:     parse.Rule{StartLine: 1, EndLine: 1, Code: "UnrecognizedInstruction", Description: "unknown", Severity: parse.SeverityFatal},
+			expected: "::error file=Dockerfile,line=1,endLine=1,title=UnrecognizedInstruction::unknown",
+		},
+		{
+			name:     "columns on a single line",
+			path:     "Dockerfile",
+			rule:     parse.Rule{StartLine: 4, EndLine: 4, StartColumn: 11, EndColumn: 16, Code: "ExposeInvalidFormat", Description: "mapping", Severity: parse.SeverityError},
+			expected: "::error file=Dockerfile,line=4,endLine=4,col=11,endColumn=15,title=ExposeInvalidFormat::mapping",
+		},
+		{
+			name:     "columns are dropped on multiple lines",
+			path:     "Dockerfile",
+			rule:     parse.Rule{StartLine: 4, EndLine: 5, StartColumn: 3, EndColumn: 2, Code: "ParserWarning", Description: "range", Severity: parse.SeverityWarning},
+			expected: "::warning file=Dockerfile,line=4,endLine=5,title=ParserWarning::range",
+		},
+		{
+			name:     "rule without location",
+			path:     "Dockerfile",
+			rule:     parse.Rule{Code: "ParserWarning", Description: "100% broken", Severity: parse.SeverityWarning},
+			expected: "::warning file=Dockerfile,title=ParserWarning::100%25 broken",
+		},
+		{
+			name:     "info is a notice",
+			path:     "Dockerfile",
+			rule:     parse.Rule{StartLine: 3, EndLine: 3, Code: "RunCacheMount", Description: "consider a cache mount", Severity: parse.SeverityInfo},
+			expected: "::notice file=Dockerfile,line=3,endLine=3,title=RunCacheMount::consider a cache mount",
+		},
+		{
+			name:     "hint is a notice",
+			path:     "Dockerfile",
+			rule:     parse.Rule{StartLine: 1, EndLine: 1, Code: "FromPinDigest", Description: "consider pinning by digest", Severity: parse.SeverityHint},
+			expected: "::notice file=Dockerfile,line=1,endLine=1,title=FromPinDigest::consider pinning by digest",
+		},
+		{
+			name:     "property values are escaped",
+			path:     "dir,with:odd/Dockerfile",
+			rule:     parse.Rule{StartLine: 1, EndLine: 1, Code: "FromAsCasing", Description: "casing", Severity: parse.SeverityWarning},
+			expected: "::warning file=dir%2Cwith%3Aodd/Dockerfile,line=1,endLine=1,title=FromAsCasing::casing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, GitHubAnnotation(tt.path, tt.rule))
+		})
+	}
+}
+
+func TestWriteGitHub(t *testing.T) {
+	files := []File{{Path: "Dockerfile", Result: &parse.Result{Rules: []parse.Rule{
+		{StartLine: 1, EndLine: 1, Code: "FromAsCasing", Description: "casing", Severity: parse.SeverityWarning},
+		{StartLine: 2, EndLine: 2, Code: "RunMissingCommand", Description: "missing", Severity: parse.SeverityError},
+	}}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteGitHub(&buf, files))
+	require.Equal(t, "::warning file=Dockerfile,line=1,endLine=1,title=FromAsCasing::casing\n"+
+		"::error file=Dockerfile,line=2,endLine=2,title=RunMissingCommand::missing\n", buf.String())
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+type gitlabIssue struct {
+	Type        string         `json:"type"`
+	CheckName   string         `json:"check_name"`
+	Description string         `json:"description"`
+	Categories  []string       `json:"categories"`
+	Severity    string         `json:"severity"`
+	Fingerprint string         `json:"fingerprint"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+// WriteGitLab writes the results as a GitLab Code Quality report
+func WriteGitLab(w io.Writer, files []File) error {
+	issues := []gitlabIssue{}
+	security, err := parse.Preset("security")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		path := filepath.ToSlash(file.Path)
+		occurrences := make(map[string]int)
+
+		for _, rule := range file.Result.Rules {
+			// GitLab requires a begin line, so file-level rules point at line 1
+			lines := gitlabLines{Begin: rule.StartLine, End: rule.EndLine}
+			if lines.Begin < 1 {
+				lines = gitlabLines{Begin: 1}
+			}
+
+			key := rule.Code + "\x00" + rule.Description
+			if rule.Fingerprint != "" {
+				key = rule.Fingerprint
+			}
+			occurrences[key]++
+
+			issues = append(issues, gitlabIssue{
+				Type:        "issue",
+				CheckName:   rule.Code,
+				Description: rule.Description,
+				Categories:  gitlabCategories(security, rule.Code),
+				Severity:    gitlabSeverity(rule.Severity),
+				Fingerprint: gitlabFingerprint(path, key, occurrences[key]),
+				Location:    gitlabLocation{Path: path, Lines: lines},
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
+
+// gitlabSeverity maps a dockadvisor severity to a Code Quality severity
+func gitlabSeverity(severity parse.Severity) string {
+	switch severity {
+	case parse.SeverityFatal:
+		return "blocker"
+	case parse.SeverityError:
+		return "major"
+	case parse.SeverityWarning:
+		return "minor"
+	default:
+		return "info"
+	}
+}
+
+// gitlabCategories maps a rule to its Code Quality categories from the rule
+// metadata: the rules of the security preset are security issues, the rules
+// that are errors by default bug risks, and the others style issues.
+// Custom rules aren't registered and are style issues.
+func gitlabCategories(security *parse.Config, code string) []string {
+	if enabled := security.Rules[code].Enabled; enabled != nil && *enabled {
+		return []string{"Security"}
+	}
+	if metadata, ok := parse.LookupRule(code); ok && metadata.Severity != parse.SeverityWarning {
+		return []string{"Bug Risk"}
+	}
+	return []string{"Style"}
+}
+
+// gitlabFingerprint identifies an issue by file and the rule's fingerprint,
+// or by code, description and the number of identical issues before it when
+// the rule has none. Line numbers are left out on purpose
+// so that unrelated edits above a finding do not make GitLab report it as
+// resolved and reintroduced.
+func gitlabFingerprint(path, key string, occurrence int) string {
+	sum := sha256.Sum256([]byte(path + "\x00" + key + "\x00" + strconv.Itoa(occurrence)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGitLab(t *testing.T) {
+	files := []File{
+		{
+			Path: "Dockerfile",
+			Result: &parse.Result{Rules: []parse.Rule{
+				{StartLine: 1, EndLine: 1, Code: "FromAsCasing", Description: "casing", Severity: parse.SeverityWarning},
+				{StartLine: 5, EndLine: 5, Code: "FromAsCasing", Description: "casing", Severity: parse.SeverityWarning},
+				{StartLine: 2, EndLine: 3, Code: "RunMissingCommand", Description: "missing", Severity: parse.SeverityError},
+				{StartLine: 4, EndLine: 4, Code: "UnrecognizedInstruction", Description: "unknown", Severity: parse.SeverityFatal},
+				{Code: "ParserWarning", Description: "no location", Severity: parse.SeverityWarning},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteGitLab(&buf, files))
+
+	var issues []gitlabIssue
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+	require.Len(t, issues, 5)
+
+	require.Equal(t, "FromAsCasing", issues[0].CheckName)
+	require.Equal(t, "minor", issues[0].Severity)
+	require.Equal(t, gitlabLocation{Path: "Dockerfile", Lines: gitlabLines{Begin: 1, End: 1}}, issues[0].Location)
+	require.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint, "identical findings should get distinct fingerprints")
+
+	require.Equal(t, "major", issues[2].Severity)
+	require.Equal(t, gitlabLines{Begin: 2, End: 3}, issues[2].Location.Lines)
+	require.Equal(t, "blocker", issues[3].Severity)
+	require.Equal(t, 1, issues[4].Location.Lines.Begin, "rules without lines should point at the first line")
+
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		require.False(t, seen[issue.Fingerprint], "fingerprints should be unique")
+		seen[issue.Fingerprint] = true
+	}
+}
+
+func TestGitLabCategories(t *testing.T) {
+	security, err := parse.Preset("security")
+	require.NoError(t, err)
+
+	tests := []struct {
+		code     string
+		expected []string
+	}{
+		{code: "SecretsUsedInArgOrEnv", expected: []string{"Security"}},
+		{code: "UserRoot", expected: []string{"Security"}},
+		{code: "RunMissingCommand", expected: []string{"Bug Risk"}},
+		{code: "UnrecognizedInstruction", expected: []string{"Bug Risk"}},
+		{code: "FromAsCasing", expected: []string{"Style"}},
+		{code: "LatestTag", expected: []string{"Style"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			require.Equal(t, tt.expected, gitlabCategories(security, tt.code))
+		})
+	}
+}
+
+func TestWriteGitLabEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteGitLab(&buf, []File{{Path: "Dockerfile", Result: &parse.Result{Score: 100}}}))
+	require.JSONEq(t, "[]", buf.String())
+}
+
+func TestGitLabFingerprintStable(t *testing.T) {
+	before := []File{{Path: "Dockerfile", Result: &parse.Result{Rules: []parse.Rule{
+		{StartLine: 2, EndLine: 2, Code: "WorkdirRelativePath", Description: "relative", Severity: parse.SeverityWarning},
+	}}}}
+	// The same finding after lines were inserted above it
+	after := []File{{Path: "Dockerfile", Result: &parse.Result{Rules: []parse.Rule{
+		{StartLine: 7, EndLine: 7, Code: "WorkdirRelativePath", Description: "relative", Severity: parse.SeverityWarning},
+	}}}}
+
+	var beforeBuf, afterBuf bytes.Buffer
+	require.NoError(t, WriteGitLab(&beforeBuf, before))
+	require.NoError(t, WriteGitLab(&afterBuf, after))
+
+	var beforeIssues, afterIssues []gitlabIssue
+	require.NoError(t, json.Unmarshal(beforeBuf.Bytes(), &beforeIssues))
+	require.NoError(t, json.Unmarshal(afterBuf.Bytes(), &afterIssues))
+	require.Equal(t, beforeIssues[0].Fingerprint, afterIssues[0].Fingerprint)
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+// JSONSchemaVersion is the version of the JSON report layout.
+// It is bumped whenever a field is renamed or removed; new fields may be
+// added without a version change. See docs/schemas/report.v1.json.
+const JSONSchemaVersion = 1
+
+type jsonReport struct {
+	SchemaVersion int        `json:"schemaVersion"`
+	Files         []jsonFile `json:"files"`
+}
+
+type jsonFile struct {
+	File    string       `json:"file"`
+	Rules   []parse.Rule `json:"rules"`
+	Score   int          `json:"score"`
+	Summary Summary      `json:"summary"`
+
+	// Breakdown lists the points lost to each rule code
+	Breakdown []parse.ScoreItem `json:"breakdown"`
+
+	// Suppressed is only present when suppressed rules are shown
+	Suppressed []parse.Rule `json:"suppressed,omitempty"`
+}
+
+// WriteJSON writes the results as a single JSON document
+func WriteJSON(w io.Writer, files []File) error {
+	out := jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		Files:         make([]jsonFile, 0, len(files)),
+	}
+
+	for _, file := range files {
+		// Always emit an array, never null, so consumers can iterate safely
+		rules := file.Result.Rules
+		if rules == nil {
+			rules = []parse.Rule{}
+		}
+
+		breakdown := file.Result.Breakdown
+		if breakdown == nil {
+			breakdown = []parse.ScoreItem{}
+		}
+
+		out.Files = append(out.Files, jsonFile{
+			File:    file.Path,
+			Rules:   rules,
+			Score:   file.Result.Score,
+			Summary: Summarize(rules),
+
+			Breakdown: breakdown,
+
+			Suppressed: file.Result.Suppressed,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	files := []File{
+		{
+			Path: "Dockerfile",
+			Result: &parse.Result{
+				Rules: []parse.Rule{
+					{StartLine: 1, EndLine: 1, Code: "FromAsCasing", Description: "casing", Url: "https://example.com", Severity: parse.SeverityWarning,
+						Fix: []parse.Edit{{StartLine: 1, StartColumn: 13, EndLine: 1, EndColumn: 15, NewText: "AS"}}},
+					{StartLine: 2, EndLine: 3, Code: "RunMissingCommand", Description: "missing", Severity: parse.SeverityError},
+				},
+				Score: 80,
+				Breakdown: []parse.ScoreItem{
+					{Code: "RunMissingCommand", Severity: parse.SeverityError, Count: 1, Weight: 15, Points: 15},
+					{Code: "FromAsCasing", Severity: parse.SeverityWarning, Count: 1, Weight: 5, Points: 5},
+				},
+			},
+		},
+		{
+			Path: "clean.Dockerfile",
+			Result: &parse.Result{
+				Score:      100,
+				Suppressed: []parse.Rule{{StartLine: 2, EndLine: 2, Code: "WorkdirRelativePath", Severity: parse.SeverityWarning}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, files))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, float64(JSONSchemaVersion), decoded["schemaVersion"])
+
+	outFiles, ok := decoded["files"].([]any)
+	require.True(t, ok, "expected files to be an array")
+	require.Len(t, outFiles, 2)
+
+	first := outFiles[0].(map[string]any)
+	require.Equal(t, "Dockerfile", first["file"])
+	require.Equal(t, float64(80), first["score"])
+	require.Equal(t, map[string]any{"fatal": float64(0), "error": float64(1), "warning": float64(1), "info": float64(0), "hint": float64(0)}, first["summary"])
+	require.Equal(t, map[string]any{"code": "RunMissingCommand", "severity": "error", "count": float64(1), "weight": float64(15), "points": float64(15)},
+		first["breakdown"].([]any)[0])
+
+	rules := first["rules"].([]any)
+	require.Len(t, rules, 2)
+	rule := rules[1].(map[string]any)
+	require.Equal(t, float64(2), rule["startLine"])
+	require.Equal(t, float64(3), rule["endLine"])
+	require.Equal(t, "RunMissingCommand", rule["code"])
+	require.Equal(t, "error", rule["severity"])
+	require.NotContains(t, rule, "StartLine", "line fields should use lower camel case")
+	require.NotContains(t, rule, "fix", "the fix is omitted when there is none")
+	require.Equal(t, []any{map[string]any{"startLine": float64(1), "startColumn": float64(13), "endLine": float64(1), "endColumn": float64(15), "newText": "AS"}},
+		rules[0].(map[string]any)["fix"])
+
+	require.NotContains(t, first, "suppressed", "suppressed rules are omitted when there are none")
+
+	second := outFiles[1].(map[string]any)
+	require.Equal(t, []any{}, second["rules"], "files without findings should have an empty rules array")
+	require.Len(t, second["suppressed"], 1)
+	require.Equal(t, []any{}, second["breakdown"])
+	require.Equal(t, map[string]any{"fatal": float64(0), "error": float64(0), "warning": float64(0), "info": float64(0), "hint": float64(0)}, second["summary"],
+		"suppressed rules are not counted")
+}
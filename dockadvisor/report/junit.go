@@ -0,0 +1,153 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results as JUnit XML. Each Dockerfile becomes a
+// testsuite and each rule a failing testcase; fatal rules are reported as
+// errors since they stop the Dockerfile from building at all, and info and
+// hint rules as passing testcases. Files without findings get a single
+// passing testcase so the suite is not empty.
+func WriteJUnit(w io.Writer, files []File) error {
+	suites := junitTestSuites{Name: toolName}
+
+	for _, file := range files {
+		suite := junitTestSuite{
+			Name:       file.Path,
+			Properties: []junitProperty{{Name: "score", Value: strconv.Itoa(file.Result.Score)}},
+		}
+
+		for _, rule := range file.Result.Rules {
+			testCase := junitTestCase{
+				Name:      junitTestCaseName(rule),
+				ClassName: file.Path,
+				File:      file.Path,
+				Line:      rule.StartLine,
+			}
+
+			problem := &junitProblem{
+				Message: rule.Description,
+				Type:    rule.Code,
+				Text:    junitProblemText(rule),
+			}
+			switch rule.Severity {
+			case parse.SeverityFatal:
+				testCase.Error = problem
+				suite.Errors++
+			case parse.SeverityInfo, parse.SeverityHint:
+				// Suggestions pass and keep their details as output
+				testCase.SystemOut = problem.Text
+			default:
+				testCase.Failure = problem
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      toolName,
+				ClassName: file.Path,
+				File:      file.Path,
+			})
+		}
+
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	return writeXML(w, suites)
+}
+
+// junitTestCaseName names a testcase after the rule code and its location
+func junitTestCaseName(rule parse.Rule) string {
+	if rule.StartLine < 1 {
+		return rule.Code
+	}
+	if rule.EndLine > rule.StartLine {
+		return fmt.Sprintf("%s (lines %d-%d)", rule.Code, rule.StartLine, rule.EndLine)
+	}
+	return fmt.Sprintf("%s (line %d)", rule.Code, rule.StartLine)
+}
+
+// junitProblemText builds the failure body shown by CI test reports
+func junitProblemText(rule parse.Rule) string {
+	lines := []string{
+		rule.Description,
+		"Code: " + rule.Code,
+		"Severity: " + string(rule.Severity),
+	}
+	if rule.StartLine > 0 {
+		lines = append(lines, "Line: "+strconv.Itoa(rule.StartLine))
+	}
+	if rule.Url != "" {
+		lines = append(lines, "Documentation: "+rule.Url)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeXML writes v as an indented XML document with a declaration
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
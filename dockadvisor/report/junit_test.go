@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	files := []File{
+		{
+			Path: "Dockerfile",
+			Result: &parse.Result{
+				Rules: []parse.Rule{
+					{StartLine: 1, EndLine: 1, Code: "FromAsCasing", Description: "casing", Url: "https://example.com", Severity: parse.SeverityWarning},
+					{StartLine: 2, EndLine: 3, Code: "RunMissingCommand", Description: "missing", Severity: parse.SeverityError},
+					{StartLine: 4, EndLine: 4, Code: "UnrecognizedInstruction", Description: "unknown", Severity: parse.SeverityFatal},
+					{StartLine: 5, EndLine: 5, Code: "RunCacheMount", Description: "consider a cache mount", Severity: parse.SeverityHint},
+				},
+				Score: 0,
+			},
+		},
+		{
+			Path:   "clean.Dockerfile",
+			Result: &parse.Result{Score: 100},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJUnit(&buf, files))
+	require.Contains(t, buf.String(), xml.Header)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	require.Equal(t, 5, suites.Tests)
+	require.Equal(t, 2, suites.Failures)
+	require.Equal(t, 1, suites.Errors)
+	require.Len(t, suites.Suites, 2)
+
+	suite := suites.Suites[0]
+	require.Equal(t, "Dockerfile", suite.Name)
+	require.Equal(t, []junitProperty{{Name: "score", Value: "0"}}, suite.Properties)
+	require.Len(t, suite.TestCases, 4)
+
+	warning := suite.TestCases[0]
+	require.Equal(t, "FromAsCasing (line 1)", warning.Name)
+	require.Equal(t, 1, warning.Line)
+	require.NotNil(t, warning.Failure)
+	require.Equal(t, "FromAsCasing", warning.Failure.Type)
+	require.Contains(t, warning.Failure.Text, "Severity: warning")
+	require.Contains(t, warning.Failure.Text, "Documentation: https://example.com")
+
+	require.Equal(t, "RunMissingCommand (lines 2-3)", suite.TestCases[1].Name)
+
+	fatal := suite.TestCases[2]
+	require.Nil(t, fatal.Failure)
+	require.NotNil(t, fatal.Error, "fatal rules should be reported as errors")
+
+	hint := suite.TestCases[3]
+	require.Nil(t, hint.Failure, "suggestions should pass")
+	require.Nil(t, hint.Error)
+	require.Contains(t, hint.SystemOut, "Severity: hint")
+
+	clean := suites.Suites[1]
+	require.Equal(t, 1, clean.Tests)
+	require.Nil(t, clean.TestCases[0].Failure)
+	require.Nil(t, clean.TestCases[0].Error)
+}
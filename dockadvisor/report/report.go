@@ -0,0 +1,63 @@
+// Package report renders dockadvisor results for terminals and in
+// machine-readable formats.
+package report
+
+import (
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+// File pairs a linted Dockerfile with its result
+type File struct {
+	Path   string
+	Result *parse.Result
+
+	// Content is the Dockerfile source. It is optional and only used to
+	// show the offending lines in the text report.
+	Content []byte
+}
+
+// Summary holds the number of rules found for each severity
+type Summary struct {
+	Fatal   int `json:"fatal"`
+	Error   int `json:"error"`
+	Warning int `json:"warning"`
+	Info    int `json:"info"`
+	Hint    int `json:"hint"`
+}
+
+// Problems returns the number of rules that cost points, leaving out the
+// info and hint suggestions
+func (s Summary) Problems() int {
+	return s.Fatal + s.Error + s.Warning
+}
+
+// Summarize counts the rules of each severity
+func Summarize(rules []parse.Rule) Summary {
+	var summary Summary
+	for _, rule := range rules {
+		switch rule.Severity {
+		case parse.SeverityFatal:
+			summary.Fatal++
+		case parse.SeverityError:
+			summary.Error++
+		case parse.SeverityWarning:
+			summary.Warning++
+		case parse.SeverityInfo:
+			summary.Info++
+		case parse.SeverityHint:
+			summary.Hint++
+		}
+	}
+	return summary
+}
+
+// LowestScore returns the lowest score among the files, or 100 if there are none
+func LowestScore(files []File) int {
+	lowest := 100
+	for _, file := range files {
+		if file.Result.Score < lowest {
+			lowest = file.Result.Score
+		}
+	}
+	return lowest
+}
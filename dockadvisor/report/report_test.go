@@ -0,0 +1,31 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	summary := Summarize([]parse.Rule{
+		{Severity: parse.SeverityFatal},
+		{Severity: parse.SeverityError},
+		{Severity: parse.SeverityError},
+		{Severity: parse.SeverityWarning},
+		{Severity: parse.SeverityInfo},
+		{Severity: parse.SeverityHint},
+		{Severity: parse.SeverityHint},
+	})
+
+	require.Equal(t, Summary{Fatal: 1, Error: 2, Warning: 1, Info: 1, Hint: 2}, summary)
+	require.Equal(t, 4, summary.Problems())
+}
+
+func TestLowestScore(t *testing.T) {
+	require.Equal(t, 100, LowestScore(nil))
+	require.Equal(t, 70, LowestScore([]File{
+		{Path: "a/Dockerfile", Result: &parse.Result{Score: 95}},
+		{Path: "b/Dockerfile", Result: &parse.Result{Score: 70}},
+	}))
+}
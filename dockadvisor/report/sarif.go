@@ -0,0 +1,213 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"slices"
+
+	"github.com/deckrun/dockadvisor/catalog"
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName       = "dockadvisor"
+	toolURI        = "https://github.com/deckrun/dockadvisor"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                     `json:"name"`
+	InformationURI string                     `json:"informationUri"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	RuleIndex  int               `json:"ruleIndex"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind string `json:"kind"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	EndLine     int `json:"endLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// WriteSARIF writes the results as a SARIF 2.1.0 log with a single run,
+// so results for several Dockerfiles can be uploaded together
+func WriteSARIF(w io.Writer, files []File) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolURI,
+			Rules:          []sarifReportingDescriptor{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	// Each rule code is published once as a reportingDescriptor and
+	// referenced from results by index
+	ruleIndexes := make(map[string]int)
+
+	for _, file := range files {
+		rules := append(slices.Clip(file.Result.Rules), file.Result.Suppressed...)
+		for i, rule := range rules {
+			index, ok := ruleIndexes[rule.Code]
+			if !ok {
+				index = len(run.Tool.Driver.Rules)
+				ruleIndexes[rule.Code] = index
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifDescriptor(rule))
+			}
+
+			result := sarifResult{
+				RuleID:    rule.Code,
+				RuleIndex: index,
+				Level:     sarifLevel(rule.Severity),
+				Message:   sarifMessage{Text: rule.Description},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifact(file.Path),
+					Region:           sarifRegionFor(rule),
+				}}},
+				Properties: map[string]string{"severity": string(rule.Severity)},
+			}
+			// Rules suppressed by a comment in the Dockerfile are kept as
+			// in-source suppressions so dashboards can show them as such
+			if i >= len(file.Result.Rules) {
+				result.Suppressions = []sarifSuppression{{Kind: "inSource"}}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifDescriptor describes the rule code of a finding. The default level
+// and the short description come from the rule catalog, since the finding
+// may have a configured severity; a rule that isn't in the catalog is
+// described by the finding.
+func sarifDescriptor(rule parse.Rule) sarifReportingDescriptor {
+	descriptor := sarifReportingDescriptor{
+		ID:                   rule.Code,
+		Name:                 rule.Code,
+		ShortDescription:     sarifMessage{Text: rule.Code},
+		HelpURI:              rule.Url,
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+	}
+	if metadata, ok := catalog.Lookup(rule.Code); ok {
+		descriptor.DefaultConfiguration.Level = sarifLevel(metadata.Severity)
+		if metadata.Summary != "" {
+			descriptor.ShortDescription.Text = metadata.Summary
+		}
+	}
+	return descriptor
+}
+
+// sarifLevel maps a dockadvisor severity to a SARIF result level.
+// SARIF has no level above "error", so fatal rules are reported as errors
+// and keep their original severity in the result properties.
+func sarifLevel(severity parse.Severity) string {
+	switch severity {
+	case parse.SeverityFatal, parse.SeverityError:
+		return "error"
+	case parse.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifArtifact builds the artifact location for a Dockerfile path.
+// Relative paths are anchored to the source root so code scanning tools
+// can resolve them against the checked out repository.
+func sarifArtifact(path string) sarifArtifactLocation {
+	location := sarifArtifactLocation{URI: filepath.ToSlash(path)}
+	if !filepath.IsAbs(path) {
+		location.URIBaseID = "%SRCROOT%"
+	}
+	return location
+}
+
+// sarifRegionFor returns the region for a rule, or nil when the rule
+// has no line information. SARIF lines and columns are 1-based and the end
+// column is exclusive, like in parse.Rule.
+func sarifRegionFor(rule parse.Rule) *sarifRegion {
+	if rule.StartLine < 1 {
+		return nil
+	}
+
+	region := &sarifRegion{StartLine: rule.StartLine}
+	if rule.EndLine >= rule.StartLine {
+		region.EndLine = rule.EndLine
+	}
+	if rule.StartColumn > 0 {
+		region.StartColumn = rule.StartColumn
+		region.EndColumn = rule.EndColumn
+	}
+	return region
+}
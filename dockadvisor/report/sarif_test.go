@@ -0,0 +1,101 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	files := []File{
+		{
+			Path: "Dockerfile",
+			Result: &parse.Result{Rules: []parse.Rule{
+				{StartLine: 1, EndLine: 1, Code: "FromAsCasing", Description: "casing", Url: "https://example.com/from-as-casing", Severity: parse.SeverityWarning},
+				{StartLine: 3, EndLine: 3, StartColumn: 1, EndColumn: 7, Code: "UnrecognizedInstruction", Description: "unknown", Severity: parse.SeverityFatal},
+			}},
+		},
+		{
+			Path: "services/api/Dockerfile",
+			Result: &parse.Result{Rules: []parse.Rule{
+				{StartLine: 2, EndLine: 4, Code: "FromAsCasing", Description: "casing again", Url: "https://example.com/from-as-casing", Severity: parse.SeverityWarning},
+				{Code: "ParserWarning", Description: "no location", Severity: parse.SeverityWarning},
+			}},
+		},
+		{
+			Path: "suppressed/Dockerfile",
+			Result: &parse.Result{Suppressed: []parse.Rule{
+				{StartLine: 1, EndLine: 1, Code: "FromAsCasing", Description: "suppressed", Severity: parse.SeverityWarning},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, files))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1, "all files should be reported in a single run")
+
+	run := log.Runs[0]
+	require.Equal(t, "dockadvisor", run.Tool.Driver.Name)
+
+	// Rule codes are deduplicated into reporting descriptors
+	require.Len(t, run.Tool.Driver.Rules, 3)
+	require.Equal(t, "FromAsCasing", run.Tool.Driver.Rules[0].ID)
+	require.Equal(t, "https://example.com/from-as-casing", run.Tool.Driver.Rules[0].HelpURI)
+	require.Equal(t, "error", run.Tool.Driver.Rules[1].DefaultConfiguration.Level)
+
+	require.Len(t, run.Results, 5)
+
+	first := run.Results[0]
+	require.Equal(t, "FromAsCasing", first.RuleID)
+	require.Equal(t, 0, first.RuleIndex)
+	require.Equal(t, "warning", first.Level)
+	require.Equal(t, "Dockerfile", first.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal(t, "%SRCROOT%", first.Locations[0].PhysicalLocation.ArtifactLocation.URIBaseID)
+
+	fatal := run.Results[1]
+	require.Equal(t, "error", fatal.Level)
+	require.Equal(t, "fatal", fatal.Properties["severity"])
+	require.Equal(t, &sarifRegion{StartLine: 3, EndLine: 3, StartColumn: 1, EndColumn: 7}, fatal.Locations[0].PhysicalLocation.Region)
+
+	multiline := run.Results[2]
+	require.Equal(t, 0, multiline.RuleIndex)
+	require.Equal(t, "services/api/Dockerfile", multiline.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal(t, &sarifRegion{StartLine: 2, EndLine: 4}, multiline.Locations[0].PhysicalLocation.Region)
+
+	require.Nil(t, run.Results[3].Locations[0].PhysicalLocation.Region, "rules without lines should have no region")
+	require.Empty(t, run.Results[3].Suppressions)
+
+	suppressed := run.Results[4]
+	require.Equal(t, 0, suppressed.RuleIndex)
+	require.Equal(t, []sarifSuppression{{Kind: "inSource"}}, suppressed.Suppressions)
+}
+
+func TestSarifDescriptor(t *testing.T) {
+	// A configured severity doesn't change the default level of the rule
+	descriptor := sarifDescriptor(parse.Rule{Code: "FromAsCasing", Description: "casing", Severity: parse.SeverityError})
+	require.Equal(t, "warning", descriptor.DefaultConfiguration.Level)
+	require.Equal(t, "FROM and AS should use the same casing", descriptor.ShortDescription.Text)
+
+	// A rule of a custom check is described by the finding
+	descriptor = sarifDescriptor(parse.Rule{Code: "LatestTag", Description: "latest", Url: "https://example.com/latest-tag", Severity: parse.SeverityError})
+	require.Equal(t, sarifReportingDescriptor{
+		ID:                   "LatestTag",
+		Name:                 "LatestTag",
+		ShortDescription:     sarifMessage{Text: "LatestTag"},
+		HelpURI:              "https://example.com/latest-tag",
+		DefaultConfiguration: sarifConfiguration{Level: "error"},
+	}, descriptor)
+}
+
+func TestSarifLevel(t *testing.T) {
+	require.Equal(t, "error", sarifLevel(parse.SeverityFatal))
+	require.Equal(t, "error", sarifLevel(parse.SeverityError))
+	require.Equal(t, "warning", sarifLevel(parse.SeverityWarning))
+}
@@ -0,0 +1,276 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/deckrun/dockadvisor/parse"
+)
+
+// TextOptions configures WriteText
+type TextOptions struct {
+	// Color enables ANSI colors. Callers should leave it off when the
+	// output is not a terminal or NO_COLOR is set.
+	Color bool
+}
+
+// maxFrameLines is the number of source lines shown for a multi-line rule
+const maxFrameLines = 5
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiBlue  = "\x1b[34m"
+	ansiGreen = "\x1b[32m"
+)
+
+// textSeverities lists the severity groups in the order they are printed
+var textSeverities = []struct {
+	severity parse.Severity
+	title    string
+	color    string
+}{
+	{parse.SeverityFatal, "Fatal", "\x1b[1;35m"},
+	{parse.SeverityError, "Errors", "\x1b[1;31m"},
+	{parse.SeverityWarning, "Warnings", "\x1b[1;33m"},
+	{parse.SeverityInfo, "Info", "\x1b[1;36m"},
+	{parse.SeverityHint, "Hints", ansiDim},
+}
+
+// WriteText writes a human-readable report. Rules are grouped by severity
+// and, when the file content is known, shown with the offending lines.
+func WriteText(w io.Writer, files []File, opts TextOptions) error {
+	t := &textWriter{color: opts.Color}
+
+	var rules []parse.Rule
+	for i, file := range files {
+		if i > 0 {
+			t.b.WriteString("\n")
+		}
+		t.writeFile(file)
+		rules = append(rules, file.Result.Rules...)
+	}
+
+	if len(files) > 1 {
+		t.b.WriteString("\n")
+		fmt.Fprintf(&t.b, "%s %d files, %s, lowest score %s\n",
+			t.paint("Summary:", ansiBold), len(files), t.counts(Summarize(rules)), t.score(LowestScore(files)))
+	}
+
+	_, err := io.WriteString(w, t.b.String())
+	return err
+}
+
+type textWriter struct {
+	b     strings.Builder
+	color bool
+}
+
+// paint wraps s in the ANSI style when colors are enabled
+func (t *textWriter) paint(s, style string) string {
+	if !t.color || s == "" {
+		return s
+	}
+	return style + s + ansiReset
+}
+
+func (t *textWriter) writeFile(file File) {
+	t.b.WriteString(t.paint(file.Path, ansiBold) + "\n")
+
+	lines := sourceLines(file.Content)
+	for _, group := range textSeverities {
+		var rules []parse.Rule
+		for _, rule := range file.Result.Rules {
+			if rule.Severity == group.severity {
+				rules = append(rules, rule)
+			}
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].StartLine < rules[j].StartLine
+		})
+
+		t.b.WriteString("\n")
+		t.b.WriteString(t.paint(fmt.Sprintf("%s (%d)", group.title, len(rules)), group.color) + "\n")
+		for _, rule := range rules {
+			t.writeRule(file.Path, rule, lines, group.color)
+		}
+	}
+
+	// Suppressed rules are only present when the caller asked to show them
+	suppressed := file.Result.Suppressed
+	if len(suppressed) != 0 {
+		t.b.WriteString("\n")
+		t.b.WriteString(t.paint(fmt.Sprintf("Suppressed (%d)", len(suppressed)), ansiDim) + "\n")
+		for _, rule := range suppressed {
+			t.writeRule(file.Path, rule, lines, ansiDim)
+		}
+	}
+
+	var suppressedCount string
+	if len(suppressed) != 0 {
+		suppressedCount = fmt.Sprintf(", %d suppressed", len(suppressed))
+	}
+
+	t.b.WriteString("\n")
+	summary := Summarize(file.Result.Rules)
+	if summary.Problems() == 0 {
+		fmt.Fprintf(&t.b, "%s No problems found%s%s, score %s\n", t.paint("✓", ansiGreen), suggestions(summary), suppressedCount, t.score(file.Result.Score))
+		return
+	}
+	fmt.Fprintf(&t.b, "%s%s, score %s\n", t.counts(summary), suppressedCount, t.score(file.Result.Score))
+}
+
+// writeRule writes a rule heading, its code frame and documentation link
+func (t *textWriter) writeRule(path string, rule parse.Rule, lines []string, color string) {
+	label := fmt.Sprintf("%s[%s]", rule.Severity, rule.Code)
+	fmt.Fprintf(&t.b, "\n%s: %s\n", t.paint(label, color), rule.Description)
+
+	location := path
+	if rule.StartLine > 0 {
+		location += ":" + strconv.Itoa(rule.StartLine)
+		if rule.StartColumn > 0 {
+			location += ":" + strconv.Itoa(rule.StartColumn)
+		}
+	}
+	fmt.Fprintf(&t.b, "  %s %s\n", t.paint("-->", ansiBlue), location)
+
+	t.writeFrame(rule, lines, color)
+
+	if rule.Url != "" {
+		fmt.Fprintf(&t.b, "  %s %s\n", t.paint("= docs:", ansiBlue), t.paint(rule.Url, ansiDim))
+	}
+}
+
+// writeFrame prints the source lines of the rule. A single line is
+// underlined with carets, below the rule's columns when it has them, and the
+// lines of a multi-line rule are marked with ">".
+func (t *textWriter) writeFrame(rule parse.Rule, lines []string, color string) {
+	if rule.StartLine < 1 || rule.StartLine > len(lines) {
+		return
+	}
+
+	end := rule.EndLine
+	if end < rule.StartLine {
+		end = rule.StartLine
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+	truncated := false
+	if end-rule.StartLine+1 > maxFrameLines {
+		end = rule.StartLine + maxFrameLines - 1
+		truncated = true
+	}
+
+	width := len(strconv.Itoa(end))
+	gutter := func(prefix string) string {
+		return t.paint(fmt.Sprintf("%*s |", width, prefix), ansiBlue)
+	}
+
+	fmt.Fprintf(&t.b, "  %s\n", gutter(""))
+	for n := rule.StartLine; n <= end; n++ {
+		line := lines[n-1]
+		if rule.StartLine == end {
+			fmt.Fprintf(&t.b, "  %s %s\n", gutter(strconv.Itoa(n)), expandTabs(line))
+			fmt.Fprintf(&t.b, "  %s %s\n", gutter(""), t.paint(underline(line, rule.StartColumn, rule.EndColumn), color))
+			continue
+		}
+		fmt.Fprintf(&t.b, "  %s%s %s\n", gutter(strconv.Itoa(n)), t.paint(">", color), expandTabs(line))
+	}
+	if truncated {
+		fmt.Fprintf(&t.b, "  %s ...\n", gutter(""))
+	}
+}
+
+// counts formats the number of rules per severity
+func (t *textWriter) counts(summary Summary) string {
+	return fmt.Sprintf("%s (%d fatal, %d %s, %d %s)%s",
+		plural(summary.Problems(), "problem"), summary.Fatal,
+		summary.Error, pluralWord(summary.Error, "error"),
+		summary.Warning, pluralWord(summary.Warning, "warning"),
+		suggestions(summary))
+}
+
+// suggestions formats the number of info and hint rules, or "" when there
+// are none
+func suggestions(summary Summary) string {
+	if total := summary.Info + summary.Hint; total != 0 {
+		return ", " + plural(total, "suggestion")
+	}
+	return ""
+}
+
+// score formats a score out of 100
+func (t *textWriter) score(score int) string {
+	return t.paint(fmt.Sprintf("%d/100", score), ansiBold)
+}
+
+// tabWidth is the number of spaces a tab is expanded to in code frames
+const tabWidth = 4
+
+// sourceLines splits Dockerfile content into lines
+func sourceLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, "\r")
+	}
+	return lines
+}
+
+// expandTabs replaces tabs so that underlines line up with the text above
+func expandTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", strings.Repeat(" ", tabWidth))
+}
+
+// underline returns carets below the 1-based columns [startColumn,
+// endColumn) of line, or below its non-blank part when there are no columns
+func underline(line string, startColumn, endColumn int) string {
+	runes := []rune(line)
+	start, end := startColumn-1, endColumn-1
+	if startColumn < 1 || start >= len(runes) {
+		start = len(runes) - len([]rune(strings.TrimLeft(line, " \t")))
+		end = len([]rune(strings.TrimRight(line, " \t")))
+	}
+	end = min(end, len(runes))
+	if end <= start {
+		end = start + 1
+	}
+
+	var b strings.Builder
+	for i, r := range runes[:min(end, len(runes))] {
+		width := 1
+		if r == '\t' {
+			width = tabWidth
+		}
+		mark := " "
+		if i >= start {
+			mark = "^"
+		}
+		b.WriteString(strings.Repeat(mark, width))
+	}
+	if end > len(runes) {
+		b.WriteString("^")
+	}
+	return b.String()
+}
+
+func plural(n int, word string) string {
+	return strconv.Itoa(n) + " " + pluralWord(n, word)
+}
+
+func pluralWord(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
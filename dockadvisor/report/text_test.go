@@ -0,0 +1,244 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deckrun/dockadvisor/parse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteText(t *testing.T) {
+	files := []File{
+		{
+			Path:    "Dockerfile",
+			Content: []byte("FROM alpine:3.20\n\tWORKDIR app\nRUN apk add \\\n    curl\n"),
+			Result: &parse.Result{
+				Score: 80,
+				Rules: []parse.Rule{
+					{StartLine: 3, EndLine: 4, Code: "SomeWarning", Description: "Multi-line warning", Severity: parse.SeverityWarning},
+					{StartLine: 2, EndLine: 2, Code: "WorkdirRelativePath", Description: "Relative workdir", Url: "https://docs.example.com/workdir", Severity: parse.SeverityError},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, files, TextOptions{}))
+
+	expected := `Dockerfile
+
+Errors (1)
+
+error[WorkdirRelativePath]: Relative workdir
+  --> Dockerfile:2
+    |
+  2 |     WORKDIR app
+    |     ^^^^^^^^^^^
+  = docs: https://docs.example.com/workdir
+
+Warnings (1)
+
+warning[SomeWarning]: Multi-line warning
+  --> Dockerfile:3
+    |
+  3 |> RUN apk add \
+  4 |>     curl
+
+2 problems (0 fatal, 1 error, 1 warning), score 80/100
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteTextMultipleFiles(t *testing.T) {
+	files := []File{
+		{Path: "Dockerfile", Result: &parse.Result{Score: 100}},
+		{
+			Path: "api/Dockerfile",
+			Result: &parse.Result{
+				Score: 0,
+				Rules: []parse.Rule{
+					{StartLine: 2, EndLine: 2, Code: "UnrecognizedInstruction", Description: "Unknown instruction", Severity: parse.SeverityFatal},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, files, TextOptions{}))
+
+	// Without the content there is no code frame
+	expected := `Dockerfile
+
+✓ No problems found, score 100/100
+
+api/Dockerfile
+
+Fatal (1)
+
+fatal[UnrecognizedInstruction]: Unknown instruction
+  --> api/Dockerfile:2
+
+1 problem (1 fatal, 0 errors, 0 warnings), score 0/100
+
+Summary: 2 files, 1 problem (1 fatal, 0 errors, 0 warnings), lowest score 0/100
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteTextSuppressed(t *testing.T) {
+	files := []File{
+		{
+			Path:    "Dockerfile",
+			Content: []byte("FROM alpine:3.20\n# dockadvisor ignore=WorkdirRelativePath\nWORKDIR app\n"),
+			Result: &parse.Result{
+				Score: 100,
+				Suppressed: []parse.Rule{
+					{StartLine: 3, EndLine: 3, StartColumn: 9, EndColumn: 12, Code: "WorkdirRelativePath", Description: "Relative workdir", Severity: parse.SeverityWarning},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, files, TextOptions{}))
+
+	expected := `Dockerfile
+
+Suppressed (1)
+
+warning[WorkdirRelativePath]: Relative workdir
+  --> Dockerfile:3:9
+    |
+  3 | WORKDIR app
+    |         ^^^
+
+✓ No problems found, 1 suppressed, score 100/100
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteTextSuggestions(t *testing.T) {
+	files := []File{
+		{
+			Path:    "Dockerfile",
+			Content: []byte("FROM alpine:3.20\nRUN apk add curl\n"),
+			Result: &parse.Result{
+				Score: 100,
+				Rules: []parse.Rule{
+					{StartLine: 2, EndLine: 2, Code: "RunCacheMount", Description: "Consider a cache mount", Severity: parse.SeverityHint},
+					{StartLine: 1, EndLine: 1, Code: "FromPinDigest", Description: "Consider pinning by digest", Severity: parse.SeverityInfo},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, files, TextOptions{}))
+
+	expected := `Dockerfile
+
+Info (1)
+
+info[FromPinDigest]: Consider pinning by digest
+  --> Dockerfile:1
+    |
+  1 | FROM alpine:3.20
+    | ^^^^^^^^^^^^^^^^
+
+Hints (1)
+
+hint[RunCacheMount]: Consider a cache mount
+  --> Dockerfile:2
+    |
+  2 | RUN apk add curl
+    | ^^^^^^^^^^^^^^^^
+
+✓ No problems found, 2 suggestions, score 100/100
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteTextColor(t *testing.T) {
+	files := []File{
+		{
+			Path:    "Dockerfile",
+			Content: []byte("FROM alpine\nWORKDIR app\n"),
+			Result: &parse.Result{
+				Score: 85,
+				Rules: []parse.Rule{
+					{StartLine: 2, EndLine: 2, Code: "WorkdirRelativePath", Description: "Relative workdir", Severity: parse.SeverityError},
+				},
+			},
+		},
+	}
+
+	var plain, colored bytes.Buffer
+	require.NoError(t, WriteText(&plain, files, TextOptions{}))
+	require.NoError(t, WriteText(&colored, files, TextOptions{Color: true}))
+
+	require.NotContains(t, plain.String(), "\x1b[")
+	require.Contains(t, colored.String(), "\x1b[1;31merror[WorkdirRelativePath]\x1b[0m")
+	require.Contains(t, colored.String(), "\x1b[1;31m^^^^^^^^^^^\x1b[0m")
+}
+
+func TestWriteTextLongFrame(t *testing.T) {
+	files := []File{
+		{
+			Path:    "Dockerfile",
+			Content: []byte("FROM alpine\nRUN a \\\n b \\\n c \\\n d \\\n e \\\n f\n"),
+			Result: &parse.Result{
+				Score: 95,
+				Rules: []parse.Rule{
+					{StartLine: 2, EndLine: 7, Code: "SomeWarning", Description: "Long warning", Severity: parse.SeverityWarning},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, files, TextOptions{}))
+	require.Contains(t, buf.String(), "  6 |>  e \\\n    | ...\n")
+	require.NotContains(t, buf.String(), " f\n")
+}
+
+func TestUnderline(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		startColumn int
+		endColumn   int
+		expected    string
+	}{
+		{
+			name:     "whole line without columns",
+			line:     "  EXPOSE 80:80  ",
+			expected: "  ^^^^^^^^^^^^",
+		},
+		{
+			name:        "columns",
+			line:        "EXPOSE 80 443 80:80",
+			startColumn: 15,
+			endColumn:   20,
+			expected:    "              ^^^^^",
+		},
+		{
+			name:        "tabs are expanded",
+			line:        "\tRUN --mount=x",
+			startColumn: 6,
+			endColumn:   15,
+			expected:    "        ^^^^^^^^^",
+		},
+		{
+			name:     "empty line",
+			line:     "",
+			expected: "^",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, underline(tt.line, tt.startColumn, tt.endColumn))
+		})
+	}
+}
@@ -3,14 +3,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"syscall/js"
 
+	"github.com/deckrun/dockadvisor/config"
 	"github.com/deckrun/dockadvisor/parse"
 )
 
-// parseDockerfileLogic contains the core business logic without JS dependencies
-func parseDockerfileLogic(dockerfileContent string) (response map[string]any) {
+// parseDockerfileLogic contains the core business logic without JS dependencies.
+// configYAML holds the content of a .dockadvisor.yaml file and may be empty.
+func parseDockerfileLogic(input parse.LintInput, configYAML string) (response map[string]any) {
 	// Recover from panics (e.g., from log.Fatal calls in the parser)
 	defer func() {
 		if r := recover(); r != nil {
@@ -21,7 +24,23 @@ func parseDockerfileLogic(dockerfileContent string) (response map[string]any) {
 		}
 	}()
 
-	result, err := parse.ParseDockerfile(dockerfileContent)
+	var cfg *parse.Config
+	if configYAML != "" {
+		file, err := config.Parse([]byte(configYAML))
+		if err != nil {
+			return map[string]any{
+				"success": false,
+				"error":   "invalid config: " + err.Error(),
+			}
+		}
+		// Without a file path, overrides never apply
+		cfg = &file.Config
+		if input.Filename != "" {
+			cfg = file.Profile(input.Filename).Config
+		}
+	}
+
+	result, err := parse.Lint(context.Background(), input, parse.Options{Config: cfg})
 	if err != nil {
 		return map[string]any{
 			"success": false,
@@ -37,24 +56,84 @@ func parseDockerfileLogic(dockerfileContent string) (response map[string]any) {
 		}
 	}
 
-	// Convert result to a format suitable for JavaScript
-	rules := make([]any, 0, len(result.Rules))
-	for _, r := range result.Rules {
-		rules = append(rules, map[string]any{
+	return map[string]any{
+		"success":    true,
+		"rules":      rulesToJS(result.Rules),
+		"suppressed": rulesToJS(result.Suppressed),
+		"score":      result.Score,
+		"breakdown":  breakdownToJS(result.Breakdown),
+		"stages":     stagesToJS(result.Stages),
+	}
+}
+
+// stagesToJS converts the build stages to a format suitable for JavaScript
+func stagesToJS(stages []parse.Stage) []any {
+	items := make([]any, 0, len(stages))
+	for _, stage := range stages {
+		items = append(items, map[string]any{
+			"index":     stage.Index,
+			"name":      stage.Name,
+			"image":     stage.Image,
+			"startLine": stage.StartLine,
+			"endLine":   stage.EndLine,
+			"used":      stage.Used,
+		})
+	}
+	return items
+}
+
+// breakdownToJS converts the score breakdown to a format suitable for JavaScript
+func breakdownToJS(breakdown []parse.ScoreItem) []any {
+	items := make([]any, 0, len(breakdown))
+	for _, item := range breakdown {
+		items = append(items, map[string]any{
+			"code":     item.Code,
+			"severity": string(item.Severity),
+			"count":    item.Count,
+			"weight":   item.Weight,
+			"points":   item.Points,
+		})
+	}
+	return items
+}
+
+// rulesToJS converts rules to a format suitable for JavaScript
+func rulesToJS(parseRules []parse.Rule) []any {
+	rules := make([]any, 0, len(parseRules))
+	for _, r := range parseRules {
+		rule := map[string]any{
 			"startLine":   r.StartLine,
 			"endLine":     r.EndLine,
+			"startColumn": r.StartColumn,
+			"endColumn":   r.EndColumn,
 			"code":        r.Code,
 			"description": r.Description,
 			"url":         r.Url,
 			"severity":    string(r.Severity),
-		})
+			"fingerprint": r.Fingerprint,
+		}
+		// Like the JSON report, fix is only set when there is one
+		if len(r.Fix) > 0 {
+			rule["fix"] = editsToJS(r.Fix)
+		}
+		rules = append(rules, rule)
 	}
+	return rules
+}
 
-	return map[string]any{
-		"success": true,
-		"rules":   rules,
-		"score":   result.Score,
+// editsToJS converts the edits of a fix to a format suitable for JavaScript
+func editsToJS(parseEdits []parse.Edit) []any {
+	edits := make([]any, 0, len(parseEdits))
+	for _, e := range parseEdits {
+		edits = append(edits, map[string]any{
+			"startLine":   e.StartLine,
+			"startColumn": e.StartColumn,
+			"endLine":     e.EndLine,
+			"endColumn":   e.EndColumn,
+			"newText":     e.NewText,
+		})
 	}
+	return edits
 }
 
 func parseDockerfile(_ js.Value, args []js.Value) interface{} {
@@ -65,7 +144,44 @@ func parseDockerfile(_ js.Value, args []js.Value) interface{} {
 		}
 	}
 	dockerfileContent := args[0].String()
-	return parseDockerfileLogic(dockerfileContent)
+
+	// The configuration is optional since the browser has no project
+	// directory to discover it from
+	var configYAML string
+	if len(args) > 1 && args[1].Type() == js.TypeString {
+		configYAML = args[1].String()
+	}
+
+	input := parse.LintInput{Content: dockerfileContent}
+	if len(args) > 2 && args[2].Type() == js.TypeObject {
+		input = lintInputFromJS(args[2], dockerfileContent)
+	}
+	return parseDockerfileLogic(input, configYAML)
+}
+
+// lintInputFromJS reads the optional { filename, buildArgs, target,
+// platform } options of parseDockerfile
+func lintInputFromJS(options js.Value, dockerfileContent string) parse.LintInput {
+	input := parse.LintInput{Content: dockerfileContent}
+	stringOption := func(name string) string {
+		if value := options.Get(name); value.Type() == js.TypeString {
+			return value.String()
+		}
+		return ""
+	}
+	input.Filename = stringOption("filename")
+	input.Target = stringOption("target")
+	input.Platform = stringOption("platform")
+
+	if buildArgs := options.Get("buildArgs"); buildArgs.Type() == js.TypeObject {
+		keys := js.Global().Get("Object").Call("keys", buildArgs)
+		input.BuildArgs = make(map[string]string, keys.Length())
+		for i := range keys.Length() {
+			name := keys.Index(i).String()
+			input.BuildArgs[name] = buildArgs.Get(name).String()
+		}
+	}
+	return input
 }
 
 func main() {
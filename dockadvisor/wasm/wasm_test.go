@@ -130,3 +130,93 @@ CMD ["/app"]`,
 		})
 	}
 }
+
+func TestWASMParseDockerfileWithConfig(t *testing.T) {
+	dockerfileContent := "FROM ubuntu:20.04\nWORKDIR app\nEXPOSE 80:80\n"
+
+	t.Run("config disables and overrides rules", func(t *testing.T) {
+		configYAML := "rules:\n  WorkdirRelativePath:\n    enabled: false\n  ExposeInvalidFormat:\n    severity: warning\n"
+		result, ok := parseDockerfile(js.Undefined(), []js.Value{js.ValueOf(dockerfileContent), js.ValueOf(configYAML)}).(map[string]any)
+		require.True(t, ok, "expected result to be map[string]any")
+		require.Equal(t, true, result["success"])
+
+		rules := result["rules"].([]any)
+		require.Len(t, rules, 1)
+		rule := rules[0].(map[string]any)
+		require.Equal(t, "ExposeInvalidFormat", rule["code"])
+		require.Equal(t, "warning", rule["severity"])
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		configYAML := "rules:\n  WorkdirRelativePath:\n    severity: critical\n"
+		result, ok := parseDockerfile(js.Undefined(), []js.Value{js.ValueOf(dockerfileContent), js.ValueOf(configYAML)}).(map[string]any)
+		require.True(t, ok, "expected result to be map[string]any")
+		require.Equal(t, false, result["success"])
+		require.Contains(t, result["error"], "invalid config")
+	})
+}
+
+func TestWASMParseDockerfileSuppressed(t *testing.T) {
+	dockerfileContent := "FROM ubuntu:20.04\n# dockadvisor ignore=WorkdirRelativePath\nWORKDIR app\n"
+
+	result, ok := parseDockerfile(js.Undefined(), []js.Value{js.ValueOf(dockerfileContent)}).(map[string]any)
+	require.True(t, ok, "expected result to be map[string]any")
+	require.Equal(t, true, result["success"])
+	require.Empty(t, result["rules"])
+	require.Equal(t, 100, result["score"])
+
+	suppressed := result["suppressed"].([]any)
+	require.Len(t, suppressed, 1)
+	require.Equal(t, "WorkdirRelativePath", suppressed[0].(map[string]any)["code"])
+}
+
+func TestWASMParseDockerfileOptions(t *testing.T) {
+	dockerfileContent := "ARG BASE\nFROM ${BASE}:latest AS Build\nWORKDIR app\n\nFROM alpine AS release\nWORKDIR /app\n"
+
+	options := js.ValueOf(map[string]any{
+		"filename":  "app/Dockerfile",
+		"buildArgs": map[string]any{"BASE": "golang"},
+		"target":    "release",
+		"platform":  "linux/arm64",
+	})
+	result, ok := parseDockerfile(js.Undefined(), []js.Value{js.ValueOf(dockerfileContent), js.Undefined(), options}).(map[string]any)
+	require.True(t, ok, "expected result to be map[string]any")
+	require.Equal(t, true, result["success"])
+	require.Empty(t, result["rules"], "the build stage isn't needed by the target and BASE is provided")
+
+	stages := result["stages"].([]any)
+	require.Len(t, stages, 2)
+	require.Equal(t, false, stages[0].(map[string]any)["used"])
+	require.Equal(t, true, stages[1].(map[string]any)["used"])
+
+	options = js.ValueOf(map[string]any{"target": "missing"})
+	result, ok = parseDockerfile(js.Undefined(), []js.Value{js.ValueOf(dockerfileContent), js.Undefined(), options}).(map[string]any)
+	require.True(t, ok, "expected result to be map[string]any")
+	require.Equal(t, false, result["success"])
+	require.Contains(t, result["error"], `target stage "missing"`)
+}
+
+func TestWASMParseDockerfileFix(t *testing.T) {
+	dockerfileContent := "FROM ubuntu:20.04\nEXPOSE 80/TCP\nWORKDIR app\n"
+
+	result, ok := parseDockerfile(js.Undefined(), []js.Value{js.ValueOf(dockerfileContent)}).(map[string]any)
+	require.True(t, ok, "expected result to be map[string]any")
+	require.Equal(t, true, result["success"])
+
+	rules := result["rules"].([]any)
+	require.Len(t, rules, 2)
+	for _, r := range rules {
+		rule := r.(map[string]any)
+		if rule["code"] != "ExposeProtoCasing" {
+			require.NotContains(t, rule, "fix", "%s has no fix", rule["code"])
+			continue
+		}
+		require.Equal(t, []any{map[string]any{
+			"startLine":   2,
+			"startColumn": 8,
+			"endLine":     2,
+			"endColumn":   14,
+			"newText":     "80/tcp",
+		}}, rule["fix"])
+	}
+}
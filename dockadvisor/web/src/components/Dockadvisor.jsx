@@ -14,7 +14,7 @@ import {
   ShareIcon
 } from '@heroicons/react/24/outline'
 import {LockClosedIcon, ExclamationTriangleIcon} from '@heroicons/react/16/solid'
-import {ExclamationCircleIcon} from '@heroicons/react/24/solid'
+import {ExclamationCircleIcon, InformationCircleIcon, LightBulbIcon} from '@heroicons/react/24/solid'
 import {ProgressBar} from "react-loader-spinner";
 import {Menu, MenuButton, MenuItem, MenuItems} from '@headlessui/react'
 import LZString from 'lz-string'
@@ -386,14 +386,27 @@ export function DockadvisorEditor({score, setScore, isEmpty, setIsEmpty, isCalcu
         setScore(result.score ?? 100);
         setIsCalculating(false);
 
-        const decorations = result.rules.map(rule => ({
-          range: new monaco.Range(rule.startLine, 1, rule.endLine, 1),
-          options: {
-            isWholeLine: true,
-            className: rule.severity === 'error' || rule.severity === 'fatal' ? 'cm-line-highlight-error' : 'cm-line-highlight-warning',
-            hoverMessage: {value: rule.description},
-          },
-        }));
+        const decorations = result.rules.flatMap(rule => {
+          const kind = severityKind(rule.severity);
+          const lineDecoration = {
+            range: new monaco.Range(rule.startLine, 1, rule.endLine, 1),
+            options: {
+              isWholeLine: true,
+              className: `cm-line-highlight-${kind}`,
+              hoverMessage: {value: rule.description},
+            },
+          };
+          if (!rule.startColumn) {
+            return [lineDecoration];
+          }
+          // Underline the exact argument, flag or variable the rule points at
+          return [lineDecoration, {
+            range: new monaco.Range(rule.startLine, rule.startColumn, rule.endLine, rule.endColumn),
+            options: {
+              inlineClassName: `cm-inline-${kind}`,
+            },
+          }];
+        });
         decorationIds.current = editor.deltaDecorations(decorationIds.current, decorations);
       }, 250); // Wait 250ms after user stops typing
     };
@@ -578,6 +591,11 @@ export function DockadvisorEditor({score, setScore, isEmpty, setIsEmpty, isCalcu
                   <style>{`
                 .cm-line-highlight-warning { background: rgba(251, 191, 36, 0.2); }
                 .cm-line-highlight-error { background: rgba(239, 68, 68, 0.2); }
+                .cm-inline-warning { text-decoration: underline wavy rgb(217, 119, 6); }
+                .cm-inline-error { text-decoration: underline wavy rgb(220, 38, 38); }
+                .cm-line-highlight-info { background: rgba(59, 130, 246, 0.12); }
+                .cm-inline-info { text-decoration: underline wavy rgb(37, 99, 235); }
+                .cm-inline-hint { text-decoration: underline dotted rgb(156, 163, 175); }
                 .cm-glyph-error { background: url('data:image/svg+xml;utf8,<svg .../>') center/contain no-repeat; width:16px; height:16px; }
             `}</style>
                 </div>
@@ -727,6 +745,21 @@ export function ScoreGauge({score, isEmpty, isCalculating}) {
   );
 }
 
+// severityKind groups a rule severity into the styles of the editor and the
+// rule list. Info and hint rules are suggestions that don't affect the score.
+function severityKind(severity) {
+  if (severity === 'error' || severity === 'fatal') return 'error';
+  if (severity === 'info' || severity === 'hint') return severity;
+  return 'warning';
+}
+
+const severityStyles = {
+  error: {Icon: ExclamationCircleIcon, iconColor: 'text-red-500', hoverBorderColor: 'hover:border-red-400'},
+  warning: {Icon: ExclamationTriangleIcon, iconColor: 'text-yellow-500', hoverBorderColor: 'hover:border-yellow-400'},
+  info: {Icon: InformationCircleIcon, iconColor: 'text-blue-500', hoverBorderColor: 'hover:border-blue-400'},
+  hint: {Icon: LightBulbIcon, iconColor: 'text-gray-400', hoverBorderColor: 'hover:border-gray-300'},
+};
+
 function RuleWarning({rule, onGoToLine}) {
   const handleClick = (e) => {
     // Don't trigger if clicking on the learn more link
@@ -736,10 +769,7 @@ function RuleWarning({rule, onGoToLine}) {
     onGoToLine(rule.startLine);
   };
 
-  const isError = rule.severity === 'error' || rule.severity === 'fatal';
-  const Icon = isError ? ExclamationCircleIcon : ExclamationTriangleIcon;
-  const iconColor = isError ? 'text-red-500' : 'text-yellow-500';
-  const hoverBorderColor = isError ? 'hover:border-red-400' : 'hover:border-yellow-400';
+  const {Icon, iconColor, hoverBorderColor} = severityStyles[severityKind(rule.severity)];
 
   return (
     <div